@@ -6,11 +6,14 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // embed the zone database so resource time zones resolve in minimal images
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -31,17 +34,63 @@ func init() {
 
 func main() {
 	var (
-		configPath         = flag.String("config", "", "Path to configuration file (optional)")
+		configPath         = flag.String("config", "", "Path to configuration file, directory or glob pattern (optional)")
+		migrateConfig      = flag.Bool("migrate-config", false, "Print the --config file migrated to the current version and exit.")
 		remoteConfigURL    = flag.String("remote-config", "", "URL for remote configuration (optional)")
+		remoteTokenFile    = flag.String("remote-bearer-token-file", "", "File holding the bearer token for the remote configuration endpoint, re-read on every request")
+		remoteUsername     = flag.String("remote-basic-auth-username", "", "Username for HTTP basic auth against the remote configuration endpoint")
+		remotePasswordFile = flag.String("remote-basic-auth-password-file", "", "File holding the HTTP basic auth password, re-read on every request")
+		remoteCAFile       = flag.String("remote-ca-file", "", "PEM bundle of additional CAs trusted for the remote configuration endpoint")
+		remoteCertFile     = flag.String("remote-client-cert", "", "Client certificate for mutual TLS with the remote configuration endpoint")
+		remoteKeyFile      = flag.String("remote-client-key", "", "Client key for mutual TLS with the remote configuration endpoint")
+		remoteTimeout      = flag.Duration("remote-timeout", 10*time.Second, "Timeout for each request to the remote configuration endpoint")
+		remoteMaxRetries   = flag.Int("remote-max-retries", 3, "Retries after a failed remote configuration fetch")
+		remoteBackoff      = flag.Duration("remote-retry-backoff", time.Second, "Initial wait between remote configuration retries, doubled for each further retry")
+		remoteMaxBackoff   = flag.Duration("remote-max-retry-backoff", 30*time.Second, "Longest wait between remote configuration retries")
+		remoteBreakerAfter = flag.Int("remote-breaker-threshold", 5, "Consecutive failed remote configuration fetches that open the circuit breaker, 0 to disable it")
+		remoteBreakerOpen  = flag.Duration("remote-breaker-open-duration", 5*time.Minute, "How long an open circuit breaker skips remote configuration fetches")
+		remoteMaxStale     = flag.Duration("remote-max-staleness", 0, "How long cached remote configuration is used while the endpoint fails before --remote-stale-policy applies, 0 for no limit")
+		remoteStalePolicy  = flag.String("remote-stale-policy", "keep", "What happens to remote resources past --remote-max-staleness: keep, freeze or revert")
+		remotePublicKeys   = flag.String("remote-public-keys", "", "PEM or JWK set file of Ed25519 keys; when set, remote configuration must be signed by one of them")
+		gitURL             = flag.String("git-url", "", "Git repository holding the configuration: a remote URL, file:// URL or local path")
+		gitRef             = flag.String("git-ref", "", "Branch or tag of --git-url to check out, the repository's HEAD if empty")
+		gitPath            = flag.String("git-path", "", "Configuration file, directory or glob pattern within the Git repository, the root if empty")
+		gitDir             = flag.String("git-checkout-dir", "", "Directory the Git repository is checked out to, a temporary directory if empty")
+		s3Endpoint         = flag.String("s3-endpoint", "", "Base URL of the S3-compatible service holding the configuration, such as http://minio:9000")
+		s3Bucket           = flag.String("s3-bucket", "", "Bucket holding the configuration objects")
+		s3Prefix           = flag.String("s3-prefix", "", "Prefix of the configuration objects in --s3-bucket")
+		s3Region           = flag.String("s3-region", "us-east-1", "Region S3 requests are signed for")
+		conflictPolicy     = flag.String("conflict-policy", config.ConflictPolicyPrecedence, "What happens when providers target the same workload: precedence, reject or merge")
 		pollInterval       = flag.Duration("interval", 30*time.Second, "How often to check for scaling changes")
 		enableLeaderElect  = flag.Bool("leader-elect", false, "Enable leader election for controller manager.")
 		enableConfigFile   = flag.Bool("enable-config-file", false, "Enable configuration from file.")
 		enableCRDProvider  = flag.Bool("enable-crd-provider", false, "Enable CRD-based configuration.")
 		enableRemoteConfig = flag.Bool("enable-remote-config", false, "Enable remote configuration fetching.")
+		enableConfigMap    = flag.Bool("enable-configmap-provider", false, "Enable ConfigMap-based configuration.")
+		enableGitConfig    = flag.Bool("enable-git-config", false, "Enable configuration from a Git repository.")
+		enableS3Config     = flag.Bool("enable-s3-config", false, "Enable configuration from an S3-compatible bucket.")
+		configMapNames     = flag.String("configmap-names", "", "Comma-separated ConfigMap names to read configuration from")
+		configMapSelector  = flag.String("configmap-selector", "", "Label selector for ConfigMaps to read configuration from")
 		namespace          = flag.String("namespace", "default", "Namespace to watch for ScheduledResources")
+		calendarPath       = flag.String("calendar-file", "", "Path to holiday calendar file (optional)")
+		calendarURL        = flag.String("calendar-url", "", "URL for remote holiday calendars (optional)")
+		enableCalendarCRD  = flag.Bool("enable-calendar-crd", false, "Enable HolidayCalendar CRD-based calendars.")
 	)
 	flag.Parse()
 
+	if *migrateConfig {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to read config file: %v", err)
+		}
+		migrated, err := config.MigrateConfig(*configPath, data)
+		if err != nil {
+			log.Fatalf("Failed to migrate config file: %v", err)
+		}
+		os.Stdout.Write(migrated)
+		return
+	}
+
 	// Create the controller manager
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:           scheme,
@@ -57,20 +106,62 @@ func main() {
 	// Add file-based configuration if enabled
 	if *enableConfigFile {
 		if *configPath != "" {
-			providers = append(providers, config.NewLocalProvider(*configPath))
+			localProvider := config.NewLocalProvider(*configPath)
+			if err := localProvider.Watch(); err != nil {
+				log.Printf("Warning: Failed to watch config file, re-reading it on every check: %v", err)
+			}
+			providers = append(providers, localProvider)
 			log.Printf("Enabled local config provider with path: %s", *configPath)
 		} else {
 			log.Println("Local config enabled but no path provided, skipping")
 		}
 	}
 
+	// Credentials, TLS, signature verification and retries for the remote endpoints.
+	// The remote calendar shares all but the retries, which it does not make.
+	remoteClient := config.RemoteConfig{
+		PollInterval: *pollInterval,
+		// Read from the environment so the token stays out of the command line
+		BearerToken: os.Getenv("REMOTE_BEARER_TOKEN"),
+		Timeout:     *remoteTimeout,
+		Retry: config.RetryConfig{
+			MaxRetries:       *remoteMaxRetries,
+			InitialBackoff:   *remoteBackoff,
+			MaxBackoff:       *remoteMaxBackoff,
+			FailureThreshold: *remoteBreakerAfter,
+			OpenDuration:     *remoteBreakerOpen,
+		},
+	}
+	switch {
+	case *remoteTokenFile != "":
+		remoteClient.Auth = config.BearerTokenFileAuth{Path: *remoteTokenFile}
+	case *remoteUsername != "" || *remotePasswordFile != "":
+		remoteClient.Auth = config.BasicAuth{Username: *remoteUsername, PasswordFile: *remotePasswordFile}
+	}
+	if *remoteCAFile != "" || *remoteCertFile != "" || *remoteKeyFile != "" {
+		remoteClient.TLS = &config.RemoteTLSConfig{
+			CAFile:   *remoteCAFile,
+			CertFile: *remoteCertFile,
+			KeyFile:  *remoteKeyFile,
+		}
+	}
+	if *remotePublicKeys != "" {
+		verifier, err := config.LoadSignatureVerifier(*remotePublicKeys)
+		if err != nil {
+			log.Fatalf("Failed to load remote config public keys: %v", err)
+		}
+		remoteClient.Verifier = verifier
+	}
+
 	// Add remote configuration if enabled
 	if *enableRemoteConfig {
 		if *remoteConfigURL != "" {
-			remoteProvider, err := config.NewRemoteProvider(config.RemoteConfig{
-				URL:          *remoteConfigURL,
-				PollInterval: *pollInterval,
-			})
+			remoteConfig := remoteClient
+			remoteConfig.URL = *remoteConfigURL
+			remoteConfig.MaxStaleness = *remoteMaxStale
+			remoteConfig.StalePolicy = *remoteStalePolicy
+
+			remoteProvider, err := config.NewRemoteProvider(remoteConfig)
 			if err != nil {
 				log.Printf("Warning: Failed to create remote provider: %v", err)
 			} else {
@@ -82,6 +173,51 @@ func main() {
 		}
 	}
 
+	// Add Git configuration if enabled
+	if *enableGitConfig {
+		if *gitURL != "" {
+			gitProvider, err := config.NewGitProvider(config.GitConfig{
+				URL:          *gitURL,
+				Ref:          *gitRef,
+				Path:         *gitPath,
+				Dir:          *gitDir,
+				PollInterval: *pollInterval,
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to create Git provider: %v", err)
+			} else {
+				providers = append(providers, gitProvider)
+				log.Printf("Enabled Git config provider with URL: %s", *gitURL)
+			}
+		} else {
+			log.Println("Git config enabled but no URL provided, skipping")
+		}
+	}
+
+	// Add object store configuration if enabled
+	if *enableS3Config {
+		if *s3Endpoint != "" && *s3Bucket != "" {
+			objectStoreProvider, err := config.NewObjectStoreProvider(config.ObjectStoreConfig{
+				Endpoint:        *s3Endpoint,
+				Bucket:          *s3Bucket,
+				Prefix:          *s3Prefix,
+				Region:          *s3Region,
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+				PollInterval:    *pollInterval,
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to create object store provider: %v", err)
+			} else {
+				providers = append(providers, objectStoreProvider)
+				log.Printf("Enabled object store config provider with bucket: %s/%s", *s3Bucket, *s3Prefix)
+			}
+		} else {
+			log.Println("Object store config enabled but no endpoint or bucket provided, skipping")
+		}
+	}
+
 	// Add CRD-based configuration if enabled
 	var crdProvider *config.CRDProvider
 	if *enableCRDProvider {
@@ -98,21 +234,85 @@ func main() {
 		}
 	}
 
+	// Add ConfigMap-based configuration if enabled
+	if *enableConfigMap {
+		var names []string
+		for _, name := range strings.Split(*configMapNames, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			log.Fatalf("Failed to create kubernetes client: %v", err)
+		}
+		configMapProvider, err := config.NewConfigMapProvider(config.ConfigMapConfig{
+			Namespace:     *namespace,
+			Names:         names,
+			LabelSelector: *configMapSelector,
+		}, clientset)
+		if err != nil {
+			log.Printf("Warning: Failed to create ConfigMap provider: %v", err)
+		} else {
+			providers = append(providers, configMapProvider)
+			log.Printf("Enabled ConfigMap config provider in namespace: %s", *namespace)
+		}
+	}
+
 	// Create multi-provider if we have multiple providers
 	var provider config.Provider
 	switch len(providers) {
 	case 0:
-		log.Fatal("No configuration providers enabled. Enable at least one provider using --enable-config-file, --enable-crd-provider, or --enable-remote-config")
+		log.Fatal("No configuration providers enabled. Enable at least one provider using --enable-config-file, --enable-crd-provider, --enable-remote-config, --enable-git-config, --enable-s3-config or --enable-configmap-provider")
 	case 1:
 		provider = providers[0]
 	default:
-		provider = config.NewMultiProvider(providers...)
+		multiProvider, err := config.NewMultiProvider(config.MultiProviderConfig{ConflictPolicy: *conflictPolicy}, providers...)
+		if err != nil {
+			log.Fatalf("Failed to combine configuration providers: %v", err)
+		}
+		provider = multiProvider
 		log.Printf("Using %d configuration providers", len(providers))
 	}
 
+	// Collect holiday calendar sources
+	var calendarProviders []config.CalendarProvider
+	if *calendarPath != "" {
+		calendarProviders = append(calendarProviders, config.NewLocalCalendarProvider(*calendarPath))
+		log.Printf("Enabled local calendar provider with path: %s", *calendarPath)
+	}
+	if *calendarURL != "" {
+		calendarConfig := remoteClient
+		calendarConfig.URL = *calendarURL
+		remoteCalendars, err := config.NewRemoteCalendarProvider(calendarConfig)
+		if err != nil {
+			log.Printf("Warning: Failed to create remote calendar provider: %v", err)
+		} else {
+			calendarProviders = append(calendarProviders, remoteCalendars)
+			log.Printf("Enabled remote calendar provider with URL: %s", *calendarURL)
+		}
+	}
+	if *enableCalendarCRD {
+		calendarProviders = append(calendarProviders, config.NewCRDCalendarProvider(mgr.GetClient()))
+		log.Println("Enabled HolidayCalendar CRD provider")
+	}
+
+	var calendars config.CalendarProvider
+	switch len(calendarProviders) {
+	case 0:
+	case 1:
+		calendars = calendarProviders[0]
+	default:
+		calendars = config.NewMultiCalendarProvider(calendarProviders...)
+	}
+
 	// Create the scheduler
 	sched, err := scheduler.New(provider, scheduler.Options{
 		PollInterval: *pollInterval,
+		Calendars:    calendars,
+		Config:       mgr.GetConfig(),
+		Mapper:       mgr.GetRESTMapper(),
+		Recorder:     mgr.GetEventRecorderFor("k8schedul8r-scheduler"),
 	})
 	if err != nil {
 		log.Fatalf("Failed to create scheduler: %v", err)
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// RemoteAuth adds credentials to requests sent to a remote endpoint
+type RemoteAuth interface {
+	// Apply sets the credentials on req
+	Apply(req *http.Request) error
+}
+
+// BearerTokenAuth sends a static bearer token
+type BearerTokenAuth struct {
+	Token string
+}
+
+// Apply implements RemoteAuth.Apply
+func (a BearerTokenAuth) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// BearerTokenFileAuth sends a bearer token read from a file. The file is read on every
+// request, so a rotated token, such as a projected service account token, is picked up.
+type BearerTokenFileAuth struct {
+	Path string
+}
+
+// Apply implements RemoteAuth.Apply
+func (a BearerTokenFileAuth) Apply(req *http.Request) error {
+	token, err := readSecretFile(a.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read bearer token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// BasicAuth sends HTTP basic credentials. The password is read from PasswordFile on
+// every request when set, and taken from Password otherwise; one of them is required.
+type BasicAuth struct {
+	Username     string
+	Password     string
+	PasswordFile string
+}
+
+// Apply implements RemoteAuth.Apply
+func (a BasicAuth) Apply(req *http.Request) error {
+	password := a.Password
+	if a.PasswordFile != "" {
+		var err error
+		if password, err = readSecretFile(a.PasswordFile); err != nil {
+			return fmt.Errorf("failed to read basic auth password: %w", err)
+		}
+	}
+	req.SetBasicAuth(a.Username, password)
+	return nil
+}
+
+// readSecretFile reads a credential from path, trimming surrounding whitespace
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return secret, nil
+}
+
+// RemoteTLSConfig configures TLS for a remote endpoint
+type RemoteTLSConfig struct {
+	// CAFile is a PEM bundle of CAs trusted in addition to the system roots
+	CAFile string `json:"caFile" yaml:"caFile"`
+	// CertFile and KeyFile hold the client certificate for mutual TLS. They are
+	// re-read for each new connection, so rotated certificates are picked up.
+	CertFile string `json:"certFile" yaml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile"`
+}
+
+// tlsConfig builds the crypto/tls configuration
+func (c *RemoteTLSConfig) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+	if c.CertFile != "" {
+		// Fail early on an unreadable pair rather than on the first connection
+		if _, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+
+	return cfg, nil
+}
+
+// newHTTPClient builds the HTTP client for a remote endpoint
+func newHTTPClient(config RemoteConfig) (*http.Client, error) {
+	client := &http.Client{
+		Timeout: config.Timeout,
+	}
+	if client.Timeout <= 0 {
+		client.Timeout = 10 * time.Second
+	}
+
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
+	}
+
+	return client, nil
+}
+
+// validateAuth rejects credentials that could only be sent incomplete
+func validateAuth(auth RemoteAuth) error {
+	var basic BasicAuth
+	switch a := auth.(type) {
+	case BasicAuth:
+		basic = a
+	case *BasicAuth:
+		basic = *a
+	default:
+		return nil
+	}
+	if basic.Username == "" {
+		return fmt.Errorf("basic auth username is required")
+	}
+	if basic.Password == "" && basic.PasswordFile == "" {
+		return fmt.Errorf("basic auth password or password file is required for user %q", basic.Username)
+	}
+	return nil
+}
+
+// authorize sets the configured credentials on req
+func (c RemoteConfig) authorize(req *http.Request) error {
+	if c.Auth != nil {
+		return c.Auth.Apply(req)
+	}
+	if c.BearerToken != "" {
+		return BearerTokenAuth{Token: c.BearerToken}.Apply(req)
+	}
+	return nil
+}
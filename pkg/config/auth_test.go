@@ -0,0 +1,302 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const authTestConfig = `[{"name": "test-scaler", "namespace": "default", "target": {"name": "test-deployment", "kind": "Deployment"}, "originalReplicas": 2, "windows": [{"days": ["Mon-Fri"], "startTimeOfDay": "08:00", "endTimeOfDay": "18:00", "replicas": 3}]}]`
+
+// writeTestFile writes content to name in dir and returns its path
+func writeTestFile(t *testing.T, dir, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// writeServerCA writes the certificate of a TLS test server as a PEM bundle
+func writeServerCA(t *testing.T, dir string, server *httptest.Server) string {
+	t.Helper()
+	return writeTestFile(t, dir, "ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func TestRemoteProvider_Auth(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := writeTestFile(t, dir, "token", []byte("first\n"))
+	passwordFile := writeTestFile(t, dir, "password", []byte("secret"))
+
+	var gotAuth string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, authTestConfig)
+	}))
+	defer server.Close()
+	caFile := writeServerCA(t, dir, server)
+
+	tests := []struct {
+		name     string
+		auth     RemoteAuth
+		token    string
+		wantAuth string
+	}{
+		{
+			name:     "static bearer token",
+			token:    "static",
+			wantAuth: "Bearer static",
+		},
+		{
+			name:     "bearer token file",
+			auth:     BearerTokenFileAuth{Path: tokenFile},
+			token:    "ignored",
+			wantAuth: "Bearer first",
+		},
+		{
+			name:     "basic auth",
+			auth:     BasicAuth{Username: "scheduler", PasswordFile: passwordFile},
+			wantAuth: "Basic c2NoZWR1bGVyOnNlY3JldA==",
+		},
+		{
+			name: "no credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewRemoteProvider(RemoteConfig{
+				URL:          server.URL,
+				PollInterval: time.Minute,
+				BearerToken:  tt.token,
+				Auth:         tt.auth,
+				TLS:          &RemoteTLSConfig{CAFile: caFile},
+			})
+			if err != nil {
+				t.Fatalf("NewRemoteProvider() error = %v", err)
+			}
+			defer provider.Stop()
+
+			if _, err := provider.Load(true); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if gotAuth != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", gotAuth, tt.wantAuth)
+			}
+		})
+	}
+
+	// A rotated token is sent on the next request
+	auth := BearerTokenFileAuth{Path: tokenFile}
+	writeTestFile(t, dir, "token", []byte("second"))
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err := auth.Apply(req); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer second" {
+		t.Errorf("Authorization after rotation = %q, want Bearer second", got)
+	}
+
+	writeTestFile(t, dir, "token", nil)
+	if err := auth.Apply(req); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("Apply() with empty token file error = %v, want is empty", err)
+	}
+}
+
+func TestRemoteProvider_UntrustedServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, authTestConfig)
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteProvider(RemoteConfig{URL: server.URL, PollInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("NewRemoteProvider() error = %v", err)
+	}
+	defer provider.Stop()
+
+	if _, err := provider.Load(true); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("Load() error = %v, want a certificate error", err)
+	}
+}
+
+func TestRemoteProvider_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	// Issue a client certificate from a throwaway CA
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate client key: %v", err)
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "k8schedul8r"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create client certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatalf("failed to marshal client key: %v", err)
+	}
+	certFile := writeTestFile(t, dir, "client.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}))
+	keyFile := writeTestFile(t, dir, "client-key.pem", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert)
+	var gotClient string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClient = r.TLS.PeerCertificates[0].Subject.CommonName
+		fmt.Fprint(w, authTestConfig)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writeServerCA(t, dir, server)
+
+	provider, err := NewRemoteProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: time.Minute,
+		TLS:          &RemoteTLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteProvider() error = %v", err)
+	}
+	defer provider.Stop()
+
+	if _, err := provider.Load(true); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if gotClient != "k8schedul8r" {
+		t.Errorf("client certificate CN = %q, want k8schedul8r", gotClient)
+	}
+
+	// Without a client certificate the handshake is rejected
+	withoutCert, err := NewRemoteProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: time.Minute,
+		TLS:          &RemoteTLSConfig{CAFile: caFile},
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteProvider() error = %v", err)
+	}
+	defer withoutCert.Stop()
+	if _, err := withoutCert.Load(true); err == nil {
+		t.Error("Load() without client certificate succeeded, want an error")
+	}
+}
+
+func TestNewRemoteProvider_InvalidAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		auth        RemoteAuth
+		errContains string
+	}{
+		{
+			name:        "username without password",
+			auth:        BasicAuth{Username: "scheduler"},
+			errContains: `password or password file is required for user "scheduler"`,
+		},
+		{
+			name:        "password file without username",
+			auth:        &BasicAuth{PasswordFile: "/etc/secrets/password"},
+			errContains: "username is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRemoteProvider(RemoteConfig{
+				URL:          "https://config.example.com",
+				PollInterval: time.Minute,
+				Auth:         tt.auth,
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("NewRemoteProvider() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestRemoteTLSConfig_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := writeTestFile(t, dir, "ca.pem", []byte("not a certificate"))
+
+	tests := []struct {
+		name        string
+		tls         RemoteTLSConfig
+		errContains string
+	}{
+		{
+			name:        "missing CA bundle",
+			tls:         RemoteTLSConfig{CAFile: filepath.Join(dir, "missing.pem")},
+			errContains: "failed to read CA bundle",
+		},
+		{
+			name:        "CA bundle without certificates",
+			tls:         RemoteTLSConfig{CAFile: notPEM},
+			errContains: "no certificates found",
+		},
+		{
+			name:        "certificate without key",
+			tls:         RemoteTLSConfig{CertFile: notPEM},
+			errContains: "must be set together",
+		},
+		{
+			name:        "unreadable key pair",
+			tls:         RemoteTLSConfig{CertFile: notPEM, KeyFile: notPEM},
+			errContains: "failed to load client certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig := tt.tls
+			_, err := NewRemoteProvider(RemoteConfig{
+				URL:          "https://config.example.com",
+				PollInterval: time.Minute,
+				TLS:          &tlsConfig,
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("NewRemoteProvider() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
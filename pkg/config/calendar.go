@@ -0,0 +1,220 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
+
+// CalendarProvider defines the interface for holiday calendar sources
+type CalendarProvider interface {
+	// If validate is true, the calendars will be validated before being returned
+	LoadCalendars(validate bool) ([]model.Calendar, error)
+}
+
+// validateCalendars validates every calendar and rejects duplicate names
+func validateCalendars(calendars []model.Calendar) error {
+	seen := make(map[string]bool, len(calendars))
+	for i, cal := range calendars {
+		if err := cal.Validate(); err != nil {
+			return fmt.Errorf("calendar[%d] validation failed: %w", i, err)
+		}
+		if seen[cal.Name] {
+			return fmt.Errorf("calendar[%d] validation failed: duplicate calendar name %s", i, cal.Name)
+		}
+		seen[cal.Name] = true
+	}
+	return nil
+}
+
+// LocalCalendarProvider loads calendars from a YAML or JSON file
+type LocalCalendarProvider struct {
+	path string
+}
+
+func NewLocalCalendarProvider(path string) *LocalCalendarProvider {
+	return &LocalCalendarProvider{
+		path: path,
+	}
+}
+
+// LoadCalendars implements CalendarProvider.LoadCalendars
+func (l *LocalCalendarProvider) LoadCalendars(validate bool) ([]model.Calendar, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar file: %w", err)
+	}
+
+	var calendars []model.Calendar
+	if err := unmarshalFile(l.path, data, &calendars); err != nil {
+		return nil, err
+	}
+
+	if validate {
+		if err := validateCalendars(calendars); err != nil {
+			return nil, err
+		}
+	}
+
+	return calendars, nil
+}
+
+// RemoteCalendarProvider fetches calendars from an HTTP endpoint, caching them for
+// PollInterval. Requests use the config's credentials, TLS settings and signature
+// verification, as the remote configuration provider does. Calendars are loaded on the
+// scheduler's check, so a failed fetch is not retried until the next one.
+type RemoteCalendarProvider struct {
+	config     RemoteConfig
+	httpClient *http.Client
+	calendars  []model.Calendar
+	fetchedAt  time.Time
+	mu         sync.Mutex
+}
+
+func NewRemoteCalendarProvider(config RemoteConfig) (*RemoteCalendarProvider, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("URL is required")
+	}
+
+	if config.PollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive")
+	}
+
+	if err := validateAuth(config.Auth); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RemoteCalendarProvider{
+		config:     config,
+		httpClient: httpClient,
+	}, nil
+}
+
+// LoadCalendars implements CalendarProvider.LoadCalendars. The lock is not held while
+// fetching, so a slow endpoint does not block callers that can use the cache.
+func (r *RemoteCalendarProvider) LoadCalendars(validate bool) ([]model.Calendar, error) {
+	r.mu.Lock()
+	cached, fetchedAt := r.calendars, r.fetchedAt
+	r.mu.Unlock()
+
+	if cached != nil && time.Since(fetchedAt) < r.config.PollInterval {
+		return cached, nil
+	}
+
+	calendars, err := r.fetchCalendars(validate)
+	if err != nil {
+		// On error, keep using the last good calendars if available
+		if cached != nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calendars = calendars
+	r.fetchedAt = time.Now()
+	return calendars, nil
+}
+
+// fetchCalendars fetches the calendars from the remote endpoint
+func (r *RemoteCalendarProvider) fetchCalendars(validate bool) ([]model.Calendar, error) {
+	doc, err := fetchDocument(r.httpClient, r.config, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, so one decoder handles both formats
+	calendars := []model.Calendar{}
+	if err := yaml.Unmarshal(doc.body, &calendars); err != nil {
+		return nil, fmt.Errorf("failed to parse calendars: %w", err)
+	}
+
+	if validate {
+		if err := validateCalendars(calendars); err != nil {
+			return nil, err
+		}
+	}
+
+	return calendars, nil
+}
+
+// CRDCalendarProvider lists cluster-scoped HolidayCalendar resources
+type CRDCalendarProvider struct {
+	client client.Client
+}
+
+func NewCRDCalendarProvider(client client.Client) *CRDCalendarProvider {
+	return &CRDCalendarProvider{
+		client: client,
+	}
+}
+
+// LoadCalendars implements CalendarProvider.LoadCalendars
+func (c *CRDCalendarProvider) LoadCalendars(validate bool) ([]model.Calendar, error) {
+	var list model.HolidayCalendarList
+	if err := c.client.List(context.Background(), &list); err != nil {
+		return nil, fmt.Errorf("failed to list holiday calendars: %w", err)
+	}
+
+	calendars := make([]model.Calendar, 0, len(list.Items))
+	for _, item := range list.Items {
+		calendars = append(calendars, model.Calendar{
+			Name: item.Name,
+			Days: item.Spec.Days,
+		})
+	}
+
+	if validate {
+		if err := validateCalendars(calendars); err != nil {
+			return nil, err
+		}
+	}
+
+	return calendars, nil
+}
+
+// MultiCalendarProvider combines calendars from several providers
+type MultiCalendarProvider struct {
+	providers []CalendarProvider
+}
+
+func NewMultiCalendarProvider(providers ...CalendarProvider) *MultiCalendarProvider {
+	return &MultiCalendarProvider{
+		providers: providers,
+	}
+}
+
+// LoadCalendars implements CalendarProvider.LoadCalendars
+func (m *MultiCalendarProvider) LoadCalendars(validate bool) ([]model.Calendar, error) {
+	var allCalendars []model.Calendar
+
+	for _, provider := range m.providers {
+		calendars, err := provider.LoadCalendars(validate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load from calendar provider: %w", err)
+		}
+		allCalendars = append(allCalendars, calendars...)
+	}
+
+	if validate {
+		if err := validateCalendars(allCalendars); err != nil {
+			return nil, err
+		}
+	}
+
+	return allCalendars, nil
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+const validCalendars = `- name: company-holidays
+  days:
+    - date: "2024-12-25"
+      description: Christmas Day
+    - date: "2024-12-30"
+      endDate: "2025-01-01"
+`
+
+func TestLocalCalendarProvider_LoadCalendars(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		ext         string
+		validate    bool
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "valid yaml",
+			content:  validCalendars,
+			ext:      ".yaml",
+			validate: true,
+		},
+		{
+			name:     "valid json",
+			content:  `[{"name": "company-holidays", "days": [{"date": "2024-12-25"}, {"date": "2024-12-30", "endDate": "2025-01-01"}]}]`,
+			ext:      ".json",
+			validate: true,
+		},
+		{
+			name:        "invalid date with validation",
+			content:     "- name: company-holidays\n  days:\n    - date: tomorrow\n",
+			ext:         ".yaml",
+			validate:    true,
+			wantErr:     true,
+			errContains: "calendar[0] validation failed",
+		},
+		{
+			name:        "duplicate names",
+			content:     validCalendars + validCalendars,
+			ext:         ".yaml",
+			validate:    true,
+			wantErr:     true,
+			errContains: "duplicate calendar name company-holidays",
+		},
+		{
+			name:        "unsupported file format",
+			content:     validCalendars,
+			ext:         ".txt",
+			validate:    true,
+			wantErr:     true,
+			errContains: "unsupported file format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpfile, err := os.CreateTemp("", "test-calendars-*"+tt.ext)
+			if err != nil {
+				t.Fatalf("failed to create temp file: %v", err)
+			}
+			defer os.Remove(tmpfile.Name())
+
+			if err := os.WriteFile(tmpfile.Name(), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write temp file: %v", err)
+			}
+
+			calendars, err := NewLocalCalendarProvider(tmpfile.Name()).LoadCalendars(tt.validate)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got nil")
+				} else if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("error %q does not contain %q", err.Error(), tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(calendars) != 1 {
+				t.Fatalf("expected 1 calendar, got %d", len(calendars))
+			}
+			if calendars[0].Name != "company-holidays" {
+				t.Errorf("expected calendar name company-holidays, got %s", calendars[0].Name)
+			}
+			if len(calendars[0].Days) != 2 || calendars[0].Days[1].EndDate != "2025-01-01" {
+				t.Errorf("unexpected calendar days: %+v", calendars[0].Days)
+			}
+		})
+	}
+}
+
+func TestRemoteCalendarProvider_LoadCalendars(t *testing.T) {
+	requestCount := 0
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		fmt.Fprint(w, validCalendars)
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteCalendarProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	calendars, err := provider.LoadCalendars(true)
+	if err != nil {
+		t.Fatalf("first LoadCalendars() failed: %v", err)
+	}
+	if len(calendars) != 1 || calendars[0].Name != "company-holidays" {
+		t.Fatalf("unexpected calendars: %+v", calendars)
+	}
+
+	// Within the poll interval the cached calendars are returned
+	if _, err := provider.LoadCalendars(true); err != nil {
+		t.Fatalf("second LoadCalendars() failed: %v", err)
+	}
+	if requestCount != 1 {
+		t.Errorf("expected 1 request to server, got %d", requestCount)
+	}
+
+	// After the poll interval a failed fetch falls back to the cached calendars
+	failing = true
+	time.Sleep(60 * time.Millisecond)
+	calendars, err = provider.LoadCalendars(true)
+	if err != nil {
+		t.Fatalf("LoadCalendars() after failure returned error: %v", err)
+	}
+	if requestCount != 2 {
+		t.Errorf("expected 2 requests to server, got %d", requestCount)
+	}
+	if len(calendars) != 1 {
+		t.Errorf("expected cached calendar, got %+v", calendars)
+	}
+}
+
+func TestRemoteCalendarProvider_Auth(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	verifier, err := NewSignatureVerifier(map[string]ed25519.PublicKey{"main": pub})
+	if err != nil {
+		t.Fatalf("NewSignatureVerifier() error = %v", err)
+	}
+
+	requestCount := 0
+	sign := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if r.Header.Get("Authorization") != "Bearer calendar-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Fail the first request to check that it is not retried until the next load
+		if requestCount == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if sign {
+			w.Header().Set(SignatureHeader, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(validCalendars))))
+		}
+		fmt.Fprint(w, validCalendars)
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteCalendarProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: time.Minute,
+		Auth:         BearerTokenAuth{Token: "calendar-token"},
+		Verifier:     verifier,
+		Retry:        RetryConfig{MaxRetries: 3, InitialBackoff: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	if _, err := provider.LoadCalendars(true); err == nil {
+		t.Fatal("LoadCalendars() with the endpoint failing returned no error")
+	}
+	if requestCount != 1 {
+		t.Errorf("expected 1 request to server, got %d", requestCount)
+	}
+
+	calendars, err := provider.LoadCalendars(true)
+	if err != nil {
+		t.Fatalf("LoadCalendars() failed: %v", err)
+	}
+	if len(calendars) != 1 || calendars[0].Name != "company-holidays" {
+		t.Errorf("unexpected calendars: %+v", calendars)
+	}
+	if requestCount != 2 {
+		t.Errorf("expected 2 requests to server, got %d", requestCount)
+	}
+
+	// An unsigned calendar is rejected
+	unsigned, err := NewRemoteCalendarProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: time.Minute,
+		Auth:         BearerTokenAuth{Token: "calendar-token"},
+		Verifier:     verifier,
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	sign = false
+	if _, err := unsigned.LoadCalendars(true); err == nil || !strings.Contains(err.Error(), "signature verification failed") {
+		t.Errorf("LoadCalendars() of an unsigned calendar error = %v, want signature verification failed", err)
+	}
+}
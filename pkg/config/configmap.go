@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
+
+// ConfigMapConfig holds the configuration for the ConfigMap provider
+type ConfigMapConfig struct {
+	// Namespace the ConfigMaps live in
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Names of the ConfigMaps to read; if empty every ConfigMap matching LabelSelector is read
+	Names []string `json:"names,omitempty" yaml:"names,omitempty"`
+	// LabelSelector restricts the watched ConfigMaps, e.g. "k8schedul8r.io/config=true"
+	LabelSelector string `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty"`
+	// ResyncPeriod is how often the informer resyncs, 0 disables resyncs
+	ResyncPeriod time.Duration `json:"resyncPeriod,omitempty" yaml:"resyncPeriod,omitempty"`
+}
+
+// ConfigMapProvider implements Provider for resources stored in ConfigMaps. Each
+// data key ending in .yaml, .yml or .json holds a configuration document; other keys
+// are ignored. ConfigMaps are watched through an informer, so Load reads from a
+// local cache rather than the API server, and subscribers are notified once the
+// ConfigMaps are first listed and whenever one changes.
+type ConfigMapProvider struct {
+	notifier
+	config   ConfigMapConfig
+	selector labels.Selector
+	names    map[string]bool
+	informer cache.SharedIndexInformer
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func NewConfigMapProvider(config ConfigMapConfig, client kubernetes.Interface) (*ConfigMapProvider, error) {
+	if config.Namespace == "" {
+		return nil, fmt.Errorf("namespace is required")
+	}
+	if len(config.Names) == 0 && config.LabelSelector == "" {
+		return nil, fmt.Errorf("configmap names or a label selector are required")
+	}
+
+	selector, err := labels.Parse(config.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", config.LabelSelector, err)
+	}
+
+	names := make(map[string]bool, len(config.Names))
+	for _, name := range config.Names {
+		names[name] = true
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(client, config.ResyncPeriod,
+		informers.WithNamespace(config.Namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = config.LabelSelector
+		}))
+
+	provider := &ConfigMapProvider{
+		config:   config,
+		selector: selector,
+		names:    names,
+		informer: factory.Core().V1().ConfigMaps().Informer(),
+		stopCh:   make(chan struct{}),
+	}
+
+	if _, err := provider.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: provider.changed,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Resyncs deliver ConfigMaps that did not change
+			if oldCM, ok := oldObj.(*corev1.ConfigMap); ok {
+				if newCM, ok := newObj.(*corev1.ConfigMap); ok && oldCM.ResourceVersion != "" &&
+					oldCM.ResourceVersion == newCM.ResourceVersion {
+					return
+				}
+			}
+			provider.changed(newObj)
+		},
+		DeleteFunc: provider.changed,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to watch configmaps: %w", err)
+	}
+
+	// Start watching in the background
+	go provider.informer.Run(provider.stopCh)
+	go func() {
+		if cache.WaitForCacheSync(provider.stopCh, provider.informer.HasSynced) {
+			provider.notify()
+		}
+	}()
+
+	return provider, nil
+}
+
+// String describes the provider's source
+func (c *ConfigMapProvider) String() string {
+	return "ConfigMaps in namespace " + c.config.Namespace
+}
+
+// Stop stops watching the ConfigMaps
+func (c *ConfigMapProvider) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+		c.notifier.close()
+	})
+}
+
+// changed notifies subscribers of a change to a ConfigMap the provider reads. Changes
+// during the initial list are covered by the notification once it completes.
+func (c *ConfigMapProvider) changed(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok || !c.matches(cm) || !c.informer.HasSynced() {
+		return
+	}
+	c.notify()
+}
+
+// matches reports whether the provider reads the ConfigMap
+func (c *ConfigMapProvider) matches(cm *corev1.ConfigMap) bool {
+	if !c.selector.Matches(labels.Set(cm.Labels)) {
+		return false
+	}
+	return len(c.names) == 0 || c.names[cm.Name]
+}
+
+// HasSynced reports whether the initial list of ConfigMaps has been received
+func (c *ConfigMapProvider) HasSynced() bool {
+	return c.informer.HasSynced()
+}
+
+// Load implements Provider.Load
+func (c *ConfigMapProvider) Load(validate bool) ([]model.Resource, error) {
+	if !c.informer.HasSynced() {
+		return nil, fmt.Errorf("configmaps in namespace %s not synced yet", c.config.Namespace)
+	}
+
+	var configMaps []*corev1.ConfigMap
+	for _, obj := range c.informer.GetStore().List() {
+		cm, ok := obj.(*corev1.ConfigMap)
+		if !ok || !c.matches(cm) {
+			continue
+		}
+		configMaps = append(configMaps, cm)
+	}
+
+	// Order by name and key so the result does not depend on the informer's store
+	sort.Slice(configMaps, func(i, j int) bool {
+		return configMaps[i].Name < configMaps[j].Name
+	})
+
+	var allResources []model.Resource
+	loadedAt := time.Now()
+	for _, cm := range configMaps {
+		keys := make([]string, 0, len(cm.Data))
+		for key := range cm.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			format, err := formatFromPath(key)
+			if err != nil {
+				continue
+			}
+
+			resources, err := decodeConfig([]byte(cm.Data[key]), format)
+			if err != nil {
+				return nil, fmt.Errorf("configmap %s/%s key %s: %w", cm.Namespace, cm.Name, key, err)
+			}
+
+			if validate {
+				for i := range resources {
+					if err := resources[i].Validate(); err != nil {
+						return nil, fmt.Errorf("configmap %s/%s key %s: resource[%d] validation failed: %w",
+							cm.Namespace, cm.Name, key, i, err)
+					}
+				}
+			}
+
+			provenance := &model.Provenance{
+				Provider: model.ProviderConfigMap,
+				Source:   fmt.Sprintf("ConfigMap %s/%s key %s", cm.Namespace, cm.Name, key),
+				Revision: cm.ResourceVersion,
+				LoadedAt: loadedAt,
+			}
+			for i := range resources {
+				resources[i].Provenance = provenance
+			}
+			allResources = append(allResources, resources...)
+		}
+	}
+
+	return allResources, nil
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testConfigMap(name string, labels map[string]string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+		Data: data,
+	}
+}
+
+// waitForSync waits until the provider's informer has its initial list
+func waitForSync(t *testing.T, provider *ConfigMapProvider) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !provider.HasSynced() {
+		if time.Now().After(deadline) {
+			t.Fatal("ConfigMap provider did not sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewConfigMapProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ConfigMapConfig
+		wantErr bool
+	}{
+		{
+			name:   "by name",
+			config: ConfigMapConfig{Namespace: "default", Names: []string{"schedules"}},
+		},
+		{
+			name:   "by label selector",
+			config: ConfigMapConfig{Namespace: "default", LabelSelector: "k8schedul8r.io/config=true"},
+		},
+		{
+			name:    "missing namespace",
+			config:  ConfigMapConfig{Names: []string{"schedules"}},
+			wantErr: true,
+		},
+		{
+			name:    "no names or selector",
+			config:  ConfigMapConfig{Namespace: "default"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid selector",
+			config:  ConfigMapConfig{Namespace: "default", LabelSelector: "a b c"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewConfigMapProvider(tt.config, fake.NewSimpleClientset())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewConfigMapProvider() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if provider != nil {
+				provider.Stop()
+			}
+		})
+	}
+}
+
+func TestConfigMapProvider_Load(t *testing.T) {
+	now := time.Now().Unix()
+	resourceYAML := func(name string) string {
+		return fmt.Sprintf(`- name: %s
+  namespace: default
+  target:
+    name: %s
+    kind: Deployment
+  originalReplicas: 2
+  windows:
+    - startTime: %d
+      endTime: %d
+      replicas: 3`, name, name, now, now+3600)
+	}
+	selected := map[string]string{"k8schedul8r.io/config": "true"}
+
+	tests := []struct {
+		name        string
+		config      ConfigMapConfig
+		configMaps  []*corev1.ConfigMap
+		validate    bool
+		wantNames   []string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:   "by name reads yaml and json keys and ignores others",
+			config: ConfigMapConfig{Namespace: "default", Names: []string{"schedules"}},
+			configMaps: []*corev1.ConfigMap{
+				testConfigMap("schedules", nil, map[string]string{
+					"a.yaml":    resourceYAML("app-a"),
+					"b.json":    `[{"name": "app-b", "namespace": "default", "target": {"name": "app-b", "kind": "Deployment"}, "originalReplicas": 1, "windows": []}]`,
+					"README.md": "not configuration",
+				}),
+				testConfigMap("other", nil, map[string]string{"c.yaml": resourceYAML("app-c")}),
+			},
+			validate:  true,
+			wantNames: []string{"app-a", "app-b"},
+		},
+		{
+			name:   "by label selector",
+			config: ConfigMapConfig{Namespace: "default", LabelSelector: "k8schedul8r.io/config=true"},
+			configMaps: []*corev1.ConfigMap{
+				testConfigMap("first", selected, map[string]string{"config.yaml": resourceYAML("app-a")}),
+				testConfigMap("second", selected, map[string]string{"config.yaml": resourceYAML("app-b")}),
+				testConfigMap("unlabelled", nil, map[string]string{"config.yaml": resourceYAML("app-c")}),
+			},
+			validate:  true,
+			wantNames: []string{"app-a", "app-b"},
+		},
+		{
+			name:   "parse error names the configmap and key",
+			config: ConfigMapConfig{Namespace: "default", Names: []string{"schedules"}},
+			configMaps: []*corev1.ConfigMap{
+				testConfigMap("schedules", nil, map[string]string{"broken.yaml": "- name: [unclosed"}),
+			},
+			wantErr:     true,
+			errContains: "configmap default/schedules key broken.yaml",
+		},
+		{
+			name:   "validation error names the configmap and key",
+			config: ConfigMapConfig{Namespace: "default", Names: []string{"schedules"}},
+			configMaps: []*corev1.ConfigMap{
+				testConfigMap("schedules", nil, map[string]string{"config.yaml": "- name: missing-namespace"}),
+			},
+			validate:    true,
+			wantErr:     true,
+			errContains: "key config.yaml: resource[0] validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, cm := range tt.configMaps {
+				if err := client.Tracker().Add(cm); err != nil {
+					t.Fatalf("failed to add configmap: %v", err)
+				}
+			}
+
+			provider, err := NewConfigMapProvider(tt.config, client)
+			if err != nil {
+				t.Fatalf("NewConfigMapProvider() error = %v", err)
+			}
+			defer provider.Stop()
+			waitForSync(t, provider)
+
+			resources, err := provider.Load(tt.validate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Load() error = %v, should contain %v", err, tt.errContains)
+				}
+				return
+			}
+
+			var names []string
+			for _, res := range resources {
+				names = append(names, res.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("Load() resources = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestConfigMapProvider_Watch(t *testing.T) {
+	client := fake.NewSimpleClientset(testConfigMap("schedules", nil, map[string]string{
+		"config.yaml": `[]`,
+	}))
+
+	provider, err := NewConfigMapProvider(ConfigMapConfig{Namespace: "default", Names: []string{"schedules"}}, client)
+	if err != nil {
+		t.Fatalf("NewConfigMapProvider() error = %v", err)
+	}
+	defer provider.Stop()
+	waitForSync(t, provider)
+
+	resources, err := provider.Load(false)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(resources) != 0 {
+		t.Fatalf("Load() returned %d resources, want 0", len(resources))
+	}
+
+	changes := provider.Subscribe()
+	updated := testConfigMap("schedules", nil, map[string]string{
+		"config.yaml": `- name: app-a
+  namespace: default
+  target:
+    name: app-a
+    kind: Deployment
+  originalReplicas: 1
+  windows: []`,
+	})
+	if _, err := client.CoreV1().ConfigMaps("default").Update(context.Background(), updated, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update configmap: %v", err)
+	}
+	waitForChange(t, changes)
+
+	// The informer delivers the update asynchronously
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resources, err = provider.Load(true)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(resources) == 1 && resources[0].Name == "app-a" {
+			if p := resources[0].Provenance; p == nil || p.Source != "ConfigMap default/schedules key config.yaml" {
+				t.Errorf("Load() provenance = %+v, want ConfigMap default/schedules key config.yaml", p)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Load() did not see the update, got %v", resources)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
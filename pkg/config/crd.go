@@ -15,7 +15,10 @@ type CRDConfig struct {
 	LabelSelector string `json:"labelSelector,omitempty" yaml:"labelSelector,omitempty"`
 }
 
+// CRDProvider serves the ScheduledResources the operator reconciles. Subscribers are
+// notified whenever one is created, changed or deleted.
 type CRDProvider struct {
+	notifier
 	config CRDConfig
 	client client.Client
 	scheme *runtime.Scheme
@@ -33,14 +36,32 @@ func NewCRDProvider(config CRDConfig, client client.Client, scheme *runtime.Sche
 	return provider, nil
 }
 
+// String describes the provider's source
+func (c *CRDProvider) String() string {
+	return "ScheduledResources in namespace " + c.config.Namespace
+}
+
 func (c *CRDProvider) UpdateResource(resource model.Resource) {
 	key := fmt.Sprintf("%s/%s", resource.Namespace, resource.Name)
-	c.cache.Store(key, resource)
+	previous, loaded := c.cache.Swap(key, resource)
+	// Reconciling an unchanged ScheduledResource is not a change
+	if loaded && sameRevision(previous.(model.Resource), resource) {
+		return
+	}
+	c.notify()
 }
 
 func (c *CRDProvider) DeleteResource(namespace, name string) {
 	key := fmt.Sprintf("%s/%s", namespace, name)
-	c.cache.Delete(key)
+	if _, loaded := c.cache.LoadAndDelete(key); loaded {
+		c.notify()
+	}
+}
+
+// sameRevision reports whether two resources were loaded from the same revision of their source
+func sameRevision(a, b model.Resource) bool {
+	return a.Provenance != nil && b.Provenance != nil && a.Provenance.Revision != "" &&
+		a.Provenance.Revision == b.Provenance.Revision
 }
 
 // Load implements Provider.Load
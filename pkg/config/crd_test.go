@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
+
+func TestCRDProvider_Subscribe(t *testing.T) {
+	provider, err := NewCRDProvider(CRDConfig{Namespace: "apps"}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewCRDProvider() error = %v", err)
+	}
+	changes := provider.Subscribe()
+
+	notified := func() bool {
+		select {
+		case <-changes:
+			return true
+		default:
+			return false
+		}
+	}
+
+	resource := multiTestResource("web", "apps", 3)
+	resource.Provenance = &model.Provenance{Provider: model.ProviderCRD, Revision: "100"}
+	provider.UpdateResource(resource)
+	if !notified() {
+		t.Errorf("creating a resource was not notified")
+	}
+
+	// Reconciling the same resourceVersion again is not a change
+	provider.UpdateResource(resource)
+	if notified() {
+		t.Errorf("reconciling an unchanged resource was notified")
+	}
+
+	resource.Provenance = &model.Provenance{Provider: model.ProviderCRD, Revision: "101"}
+	provider.UpdateResource(resource)
+	if !notified() {
+		t.Errorf("updating a resource was not notified")
+	}
+
+	provider.DeleteResource("apps", "web")
+	if !notified() {
+		t.Errorf("deleting a resource was not notified")
+	}
+	provider.DeleteResource("apps", "web")
+	if notified() {
+		t.Errorf("deleting a missing resource was notified")
+	}
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
+
+// Configuration formats understood by decodeConfig
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+const (
+	// CurrentConfigVersion is the configuration file version written by MigrateConfig
+	CurrentConfigVersion = "1"
+	// legacyConfigVersion stands for the original format, a bare list of resources
+	legacyConfigVersion = "0"
+)
+
+// ConfigFile is the versioned configuration envelope:
+//
+//	version: "1"
+//	defaults:
+//	  namespace: apps
+//	resources:
+//	  - name: ...
+type ConfigFile struct {
+	// Version of the file format, see CurrentConfigVersion
+	Version string `json:"version" yaml:"version"`
+	// Defaults fill in fields left empty on the resources
+	Defaults *ResourceDefaults `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	// Resources to schedule
+	Resources []model.Resource `json:"resources" yaml:"resources"`
+}
+
+// ResourceDefaults are applied to every resource in a ConfigFile that does not set the field itself
+type ResourceDefaults struct {
+	Namespace     string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	TimeZone      string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+	Calendar      string `json:"calendar,omitempty" yaml:"calendar,omitempty"`
+	OverlapPolicy string `json:"overlapPolicy,omitempty" yaml:"overlapPolicy,omitempty"`
+	ScaleMode     string `json:"scaleMode,omitempty" yaml:"scaleMode,omitempty"`
+}
+
+// migrations upgrade a ConfigFile from the version they are keyed by to the next one
+var migrations = map[string]func(*ConfigFile){
+	// A bare list has no defaults or other settings to carry over
+	legacyConfigVersion: func(cfg *ConfigFile) {
+		cfg.Version = "1"
+	},
+}
+
+// formatFromPath returns the configuration format for a file name's extension
+func formatFromPath(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported file format: %s", ext)
+	}
+}
+
+// parseConfigs reads the configuration documents in data and migrates them to the
+// current version. A YAML stream may hold several documents separated by "---"; JSON
+// holds one. Each document is either the versioned envelope or the legacy bare list.
+func parseConfigs(data []byte, format string) ([]*ConfigFile, error) {
+	switch format {
+	case FormatYAML:
+		var docs []*yaml.Node
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			doc := &yaml.Node{}
+			if err := dec.Decode(doc); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+			}
+			docs = append(docs, doc)
+		}
+		if len(docs) == 0 {
+			// An empty file holds no resources
+			docs = append(docs, &yaml.Node{})
+		}
+
+		configs := make([]*ConfigFile, 0, len(docs))
+		for i, doc := range docs {
+			cfg, err := parseYAMLDocument(doc)
+			if err != nil {
+				if len(docs) > 1 {
+					return nil, fmt.Errorf("document[%d]: %w", i, err)
+				}
+				return nil, err
+			}
+			configs = append(configs, cfg)
+		}
+		return configs, nil
+	case FormatJSON:
+		cfg, err := parseJSONDocument(data)
+		if err != nil {
+			return nil, err
+		}
+		return []*ConfigFile{cfg}, nil
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+}
+
+// parseYAMLDocument reads a single document of a YAML stream
+func parseYAMLDocument(doc *yaml.Node) (*ConfigFile, error) {
+	cfg := &ConfigFile{}
+
+	if len(doc.Content) == 0 || doc.Content[0].Tag == "!!null" {
+		// An empty document holds no resources
+		cfg.Version = legacyConfigVersion
+		return cfg, cfg.migrate()
+	}
+	root := doc.Content[0]
+	if root.Kind == yaml.SequenceNode {
+		cfg.Version = legacyConfigVersion
+		if err := root.Decode(&cfg.Resources); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+		return cfg, cfg.migrate()
+	}
+	if err := root.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+	return cfg, cfg.migrate()
+}
+
+// parseJSONDocument reads a JSON configuration document
+func parseJSONDocument(data []byte) (*ConfigFile, error) {
+	cfg := &ConfigFile{}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		cfg.Version = legacyConfigVersion
+		if err := json.Unmarshal(data, &cfg.Resources); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+		}
+		return cfg, cfg.migrate()
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+	}
+	return cfg, cfg.migrate()
+}
+
+// migrate upgrades the configuration to the current version
+func (c *ConfigFile) migrate() error {
+	if c.Version == "" {
+		return fmt.Errorf("config version is required")
+	}
+	for c.Version != CurrentConfigVersion {
+		migrate, ok := migrations[c.Version]
+		if !ok {
+			return fmt.Errorf("unsupported config version %q", c.Version)
+		}
+		migrate(c)
+	}
+	return nil
+}
+
+// decodeConfig parses the configuration documents in data and returns their resources
+// with each document's defaults applied
+func decodeConfig(data []byte, format string) ([]model.Resource, error) {
+	configs, err := parseConfigs(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []model.Resource
+	for _, cfg := range configs {
+		cfg.applyDefaults()
+		resources = append(resources, cfg.Resources...)
+	}
+	return resources, nil
+}
+
+// applyDefaults fills in the resources' empty fields from Defaults
+func (c *ConfigFile) applyDefaults() {
+	if c.Defaults == nil {
+		return
+	}
+	d := c.Defaults
+	for i := range c.Resources {
+		res := &c.Resources[i]
+		if res.Namespace == "" {
+			res.Namespace = d.Namespace
+		}
+		if res.TimeZone == "" {
+			res.TimeZone = d.TimeZone
+		}
+		if res.Calendar == "" {
+			res.Calendar = d.Calendar
+		}
+		if res.OverlapPolicy == "" {
+			res.OverlapPolicy = d.OverlapPolicy
+		}
+		if res.ScaleMode == "" {
+			res.ScaleMode = d.ScaleMode
+		}
+	}
+}
+
+// MigrateConfig rewrites a configuration file of any supported version in the current
+// version, keeping the format given by path's extension. Defaults are kept rather than
+// expanded, and each document of a YAML stream is migrated on its own.
+func MigrateConfig(path string, data []byte) ([]byte, error) {
+	format, err := formatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := parseConfigs(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatJSON {
+		out, err := json.MarshalIndent(configs[0], "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	}
+
+	var out bytes.Buffer
+	for i, cfg := range configs {
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		doc, err := yaml.Marshal(cfg)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(doc)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	legacyYAML := `- name: app-a
+  namespace: default
+  target:
+    name: app-a
+    kind: Deployment
+  originalReplicas: 2
+  windows: []`
+
+	envelopeYAML := `version: "1"
+defaults:
+  namespace: apps
+  timeZone: Europe/Berlin
+resources:
+  - name: app-a
+    target:
+      name: app-a
+      kind: Deployment
+    originalReplicas: 2
+    windows: []
+  - name: app-b
+    namespace: other
+    timeZone: UTC
+    target:
+      name: app-b
+      kind: Deployment
+    originalReplicas: 1
+    windows: []`
+
+	legacyJSON := `[{"name": "app-a", "namespace": "default", "target": {"name": "app-a", "kind": "Deployment"}, "originalReplicas": 2, "windows": []}]`
+
+	envelopeJSON := `{"version": "1", "defaults": {"namespace": "apps"}, "resources": [{"name": "app-a", "target": {"name": "app-a", "kind": "Deployment"}, "originalReplicas": 2, "windows": []}]}`
+
+	tests := []struct {
+		name           string
+		data           string
+		format         string
+		wantNamespaces []string
+		wantTimeZones  []string
+		wantErr        bool
+		errContains    string
+	}{
+		{
+			name:           "legacy yaml list",
+			data:           legacyYAML,
+			format:         FormatYAML,
+			wantNamespaces: []string{"default"},
+			wantTimeZones:  []string{""},
+		},
+		{
+			name:           "yaml envelope with defaults",
+			data:           envelopeYAML,
+			format:         FormatYAML,
+			wantNamespaces: []string{"apps", "other"},
+			wantTimeZones:  []string{"Europe/Berlin", "UTC"},
+		},
+		{
+			name:           "legacy json list",
+			data:           legacyJSON,
+			format:         FormatJSON,
+			wantNamespaces: []string{"default"},
+			wantTimeZones:  []string{""},
+		},
+		{
+			name:           "json envelope with defaults",
+			data:           envelopeJSON,
+			format:         FormatJSON,
+			wantNamespaces: []string{"apps"},
+			wantTimeZones:  []string{""},
+		},
+		{
+			name:   "empty yaml document",
+			data:   "",
+			format: FormatYAML,
+		},
+		{
+			name:           "yaml stream with per-document defaults",
+			data:           envelopeYAML + "\n---\n" + legacyYAML + "\n---\n",
+			format:         FormatYAML,
+			wantNamespaces: []string{"apps", "other", "default"},
+			wantTimeZones:  []string{"Europe/Berlin", "UTC", ""},
+		},
+		{
+			name:        "invalid document in yaml stream",
+			data:        legacyYAML + "\n---\nresources: []",
+			format:      FormatYAML,
+			wantErr:     true,
+			errContains: "document[1]: config version is required",
+		},
+		{
+			name:        "envelope without version",
+			data:        "resources: []",
+			format:      FormatYAML,
+			wantErr:     true,
+			errContains: "config version is required",
+		},
+		{
+			name:        "unknown version",
+			data:        `{"version": "9", "resources": []}`,
+			format:      FormatJSON,
+			wantErr:     true,
+			errContains: `unsupported config version "9"`,
+		},
+		{
+			name:        "invalid yaml",
+			data:        "version: [unclosed",
+			format:      FormatYAML,
+			wantErr:     true,
+			errContains: "failed to parse YAML config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := decodeConfig([]byte(tt.data), tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("decodeConfig() error = %v, should contain %v", err, tt.errContains)
+				}
+				return
+			}
+
+			if len(resources) != len(tt.wantNamespaces) {
+				t.Fatalf("decodeConfig() returned %d resources, want %d", len(resources), len(tt.wantNamespaces))
+			}
+			for i, res := range resources {
+				if res.Namespace != tt.wantNamespaces[i] {
+					t.Errorf("resource[%d] namespace = %q, want %q", i, res.Namespace, tt.wantNamespaces[i])
+				}
+				if res.TimeZone != tt.wantTimeZones[i] {
+					t.Errorf("resource[%d] time zone = %q, want %q", i, res.TimeZone, tt.wantTimeZones[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLocalProvider_LoadExample(t *testing.T) {
+	provider := NewLocalProvider("../../examples/config.yaml")
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(resources) != 1 || resources[0].Name != "my-app-scaler" {
+		t.Errorf("Load() = %+v, want the my-app-scaler resource", resources)
+	}
+}
+
+func TestMigrateConfig(t *testing.T) {
+	legacy := `- name: app-a
+  namespace: default
+  target:
+    name: app-a
+    kind: Deployment
+  originalReplicas: 2
+  windows:
+    - days: ["Mon-Fri"]
+      startTimeOfDay: "08:00"
+      endTimeOfDay: "18:00"
+      replicas: 4`
+
+	for _, path := range []string{"config.yaml", "config.json"} {
+		t.Run(path, func(t *testing.T) {
+			data := []byte(legacy)
+			if path == "config.json" {
+				data = []byte(`[{"name": "app-a", "namespace": "default", "target": {"name": "app-a", "kind": "Deployment"}, "originalReplicas": 2, "windows": [{"days": ["Mon-Fri"], "startTimeOfDay": "08:00", "endTimeOfDay": "18:00", "replicas": 4}]}]`)
+			}
+
+			migrated, err := MigrateConfig(path, data)
+			if err != nil {
+				t.Fatalf("MigrateConfig() error = %v", err)
+			}
+			if strings.Contains(string(migrated), "startTime:") || strings.Contains(string(migrated), `"startTime"`) {
+				t.Errorf("MigrateConfig() wrote unset absolute times:\n%s", migrated)
+			}
+
+			format, _ := formatFromPath(path)
+			configs, err := parseConfigs(migrated, format)
+			if err != nil {
+				t.Fatalf("parseConfigs() of migrated config error = %v\n%s", err, migrated)
+			}
+			if len(configs) != 1 {
+				t.Fatalf("migrated config has %d documents, want 1", len(configs))
+			}
+			cfg := configs[0]
+			if cfg.Version != CurrentConfigVersion {
+				t.Errorf("migrated version = %q, want %q", cfg.Version, CurrentConfigVersion)
+			}
+			if len(cfg.Resources) != 1 || len(cfg.Resources[0].Windows) != 1 || cfg.Resources[0].Windows[0].StartTimeOfDay != "08:00" {
+				t.Errorf("migrated resources = %+v", cfg.Resources)
+			}
+			if err := cfg.Resources[0].Validate(); err != nil {
+				t.Errorf("migrated resource is invalid: %v", err)
+			}
+		})
+	}
+
+	if _, err := MigrateConfig("config.toml", nil); err == nil || !strings.Contains(err.Error(), "unsupported file format") {
+		t.Errorf("MigrateConfig() error = %v, want unsupported file format", err)
+	}
+}
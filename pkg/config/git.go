@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
+
+// GitConfig holds the configuration for the Git provider
+type GitConfig struct {
+	// URL is the repository to fetch from: a remote URL, a file:// URL or a local path
+	URL string `json:"url" yaml:"url"`
+	// Ref is the branch or tag to check out, the repository's HEAD if empty
+	Ref string `json:"ref" yaml:"ref"`
+	// Path is the schedule file, directory or glob pattern within the repository,
+	// the repository root if empty
+	Path string `json:"path" yaml:"path"`
+	// Dir is where the repository is checked out, a temporary directory if empty
+	Dir string `json:"dir" yaml:"dir"`
+	// PollInterval is how often the repository is fetched, on every Load if zero
+	PollInterval time.Duration `json:"pollInterval" yaml:"pollInterval"`
+	// Timeout limits each git command, 1 minute if zero
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// GitProvider loads resources from schedule files in a Git repository. Each fetch
+// checks out the configured ref, and the commit that produced the resources is
+// recorded in their provenance and reported in the provider's revision. The last
+// commit that validated is kept, so a bad push does not drop the running configuration.
+type GitProvider struct {
+	config    GitConfig
+	dir       string
+	current   *gitRevision
+	lastErr   error
+	fetchedAt time.Time
+	mu        sync.RWMutex
+	// syncMu serializes fetches, so Revision does not wait on one
+	syncMu sync.Mutex
+}
+
+// gitRevision is a validated parse of the schedule files at a commit
+type gitRevision struct {
+	resources  []model.Resource
+	generation int64
+	hash       string
+	commit     string
+}
+
+func NewGitProvider(config GitConfig) (*GitProvider, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("URL is required")
+	}
+	if config.Path != "" && !filepath.IsLocal(config.Path) {
+		return nil, fmt.Errorf("path %q must be relative to the repository root", config.Path)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Minute
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is not installed: %w", err)
+	}
+
+	dir := config.Dir
+	if dir == "" {
+		var err error
+		if dir, err = os.MkdirTemp("", "k8schedul8r-git-"); err != nil {
+			return nil, fmt.Errorf("failed to create checkout directory: %w", err)
+		}
+	} else if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkout directory: %w", err)
+	}
+
+	return &GitProvider{
+		config: config,
+		dir:    dir,
+	}, nil
+}
+
+// String describes the provider's source
+func (g *GitProvider) String() string {
+	if g.config.Path != "" {
+		return "git " + g.source() + ":" + g.config.Path
+	}
+	return "git " + g.source()
+}
+
+// source names the repository and ref
+func (g *GitProvider) source() string {
+	if g.config.Ref != "" {
+		return g.config.URL + "@" + g.config.Ref
+	}
+	return g.config.URL
+}
+
+// Revision implements RevisionProvider.Revision
+func (g *GitProvider) Revision() Revision {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	rev := Revision{Error: g.lastErr}
+	if g.current != nil {
+		rev.Generation = g.current.generation
+		rev.Hash = g.current.hash
+		rev.Commit = g.current.commit
+	}
+	return rev
+}
+
+// Load implements Provider.Load
+func (g *GitProvider) Load(validate bool) ([]model.Resource, error) {
+	g.syncMu.Lock()
+	defer g.syncMu.Unlock()
+
+	g.mu.RLock()
+	current, fetchedAt := g.current, g.fetchedAt
+	g.mu.RUnlock()
+
+	if validate && current != nil && time.Since(fetchedAt) < g.config.PollInterval {
+		return current.resources, nil
+	}
+
+	resources, hash, commit, err := g.fetch(validate)
+	if err != nil {
+		// Keep serving the last good commit until the repository is fixed
+		if validate && current != nil {
+			g.mu.Lock()
+			g.lastErr = err
+			g.fetchedAt = time.Now()
+			g.mu.Unlock()
+			return current.resources, nil
+		}
+		return nil, err
+	}
+
+	if validate {
+		g.mu.Lock()
+		g.lastErr = nil
+		g.fetchedAt = time.Now()
+		g.swap(resources, hash, commit)
+		g.mu.Unlock()
+	}
+
+	return resources, nil
+}
+
+// swap makes resources the current revision unless the commit and content are
+// unchanged. Callers hold mu.
+func (g *GitProvider) swap(resources []model.Resource, hash, commit string) {
+	if g.current != nil && g.current.commit == commit && g.current.hash == hash {
+		return
+	}
+	var generation int64 = 1
+	if g.current != nil {
+		generation = g.current.generation + 1
+	}
+	g.current = &gitRevision{
+		resources:  resources,
+		generation: generation,
+		hash:       hash,
+		commit:     commit,
+	}
+}
+
+// fetch checks out the configured ref and parses its schedule files, returning the
+// resources, their content hash and the commit they were read from
+func (g *GitProvider) fetch(validate bool) ([]model.Resource, string, string, error) {
+	commit, err := g.checkout()
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	path := filepath.Join(g.dir, g.config.Path)
+	if !strings.ContainsAny(g.config.Path, "*?[") {
+		if _, err := os.Stat(path); err != nil {
+			return nil, "", "", fmt.Errorf("commit %.12s: %q not found in repository", commit, g.config.Path)
+		}
+	}
+
+	resources, hash, err := NewLocalProvider(path).read(validate)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("commit %.12s: %w", commit, err)
+	}
+	for i := range resources {
+		file := resources[i].Provenance.Source
+		if rel, err := filepath.Rel(g.dir, file); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+		resources[i].Provenance = &model.Provenance{
+			Provider: model.ProviderGit,
+			Source:   g.source() + ":" + file,
+			Revision: commit,
+			LoadedAt: resources[i].Provenance.LoadedAt,
+		}
+	}
+	return resources, hash, commit, nil
+}
+
+// checkout fetches the configured ref into the checkout directory, checks it out and
+// returns its commit SHA
+func (g *GitProvider) checkout() (string, error) {
+	if _, err := os.Stat(filepath.Join(g.dir, ".git")); os.IsNotExist(err) {
+		if _, err := g.git("init", "--quiet"); err != nil {
+			return "", err
+		}
+	}
+
+	ref := g.config.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := g.git("fetch", "--quiet", "--depth=1", "--", g.config.URL, ref); err != nil {
+		return "", err
+	}
+	if _, err := g.git("checkout", "--quiet", "--force", "--detach", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	// Remove anything left behind that the commit does not track
+	if _, err := g.git("clean", "--quiet", "-ffdx"); err != nil {
+		return "", err
+	}
+	return g.git("rev-parse", "HEAD")
+}
+
+// git runs a git command in the checkout directory and returns its trimmed output
+func (g *GitProvider) git(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.config.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.dir
+	// Fail instead of waiting for credentials on a terminal
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRepo is a Git repository for the provider to fetch from
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := &testRepo{t: t, dir: t.TempDir()}
+	repo.git("init", "--quiet", "--initial-branch=main")
+	return repo
+}
+
+// git runs a git command in the repository and returns its trimmed output
+func (r *testRepo) git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes files and commits them, returning the commit SHA
+func (r *testRepo) commit(files map[string]string) string {
+	r.t.Helper()
+	writeFiles(r.t, r.dir, files)
+	r.git("add", "--all")
+	r.git("commit", "--quiet", "--message", "update schedules")
+	return r.git("rev-parse", "HEAD")
+}
+
+func TestNewGitProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      GitConfig
+		errContains string
+	}{
+		{
+			name:        "missing URL",
+			config:      GitConfig{},
+			errContains: "URL is required",
+		},
+		{
+			name:        "path outside the repository",
+			config:      GitConfig{URL: "file:///repo", Path: "../schedules"},
+			errContains: "must be relative to the repository root",
+		},
+		{
+			name:   "valid",
+			config: GitConfig{URL: "file:///repo", Path: "schedules", Dir: t.TempDir()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGitProvider(tt.config)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("NewGitProvider() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("NewGitProvider() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestGitProvider_Load(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit(map[string]string{
+		"schedules/web.yaml": localConfig(3),
+		"README.md":          "not a schedule",
+	})
+	repo.git("tag", "--annotate", "v1", "--message", "v1")
+
+	provider, err := NewGitProvider(GitConfig{URL: "file://" + repo.dir, Ref: "main", Path: "schedules", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewGitProvider() error = %v", err)
+	}
+
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(resources) != 1 || resources[0].Windows[0].Replicas != 3 {
+		t.Fatalf("Load() = %+v, want one resource with 3 replicas", resources)
+	}
+	if p := resources[0].Provenance; p.Revision != first || p.Source != "file://"+repo.dir+"@main:schedules/web.yaml" {
+		t.Errorf("Load() provenance = %+v, want schedules/web.yaml at commit %s", p, first)
+	}
+	if rev := provider.Revision(); rev.Generation != 1 || rev.Commit != first {
+		t.Errorf("Revision() = %+v, want generation 1 at commit %s", rev, first)
+	}
+
+	// A new commit on the branch is picked up on the next fetch
+	second := repo.commit(map[string]string{"schedules/web.yaml": localConfig(5)})
+	resources, err = provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if resources[0].Windows[0].Replicas != 5 || resources[0].Provenance.Revision != second {
+		t.Errorf("Load() after commit = %d replicas at %q, want 5 at %q",
+			resources[0].Windows[0].Replicas, resources[0].Provenance.Revision, second)
+	}
+	if rev := provider.Revision(); rev.Generation != 2 || rev.Commit != second {
+		t.Errorf("Revision() after commit = %+v, want generation 2 at commit %s", rev, second)
+	}
+
+	// An invalid commit keeps the last good one and reports the failure
+	repo.commit(map[string]string{"schedules/web.yaml": "- name: broken"})
+	resources, err = provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if resources[0].Provenance.Revision != second {
+		t.Errorf("Load() after invalid commit commit = %q, want the last good %q", resources[0].Provenance.Revision, second)
+	}
+	rev := provider.Revision()
+	if rev.Generation != 2 || rev.Error == nil || !strings.Contains(rev.Error.Error(), "web.yaml") {
+		t.Errorf("Revision() after invalid commit = %+v, want generation 2 with an error naming web.yaml", rev)
+	}
+
+	// A tag checks out the commit it points at, from a plain local path
+	tagged, err := NewGitProvider(GitConfig{URL: repo.dir, Ref: "v1", Path: "schedules", Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewGitProvider() error = %v", err)
+	}
+	resources, err = tagged.Load(true)
+	if err != nil {
+		t.Fatalf("Load() of tag error = %v", err)
+	}
+	if resources[0].Windows[0].Replicas != 3 || resources[0].Provenance.Revision != first {
+		t.Errorf("Load() of tag = %d replicas at %q, want 3 at %q",
+			resources[0].Windows[0].Replicas, resources[0].Provenance.Revision, first)
+	}
+}
+
+func TestGitProvider_Load_PollInterval(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit(map[string]string{"web.yaml": localConfig(3)})
+
+	provider, err := NewGitProvider(GitConfig{URL: repo.dir, Dir: t.TempDir(), PollInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewGitProvider() error = %v", err)
+	}
+	if _, err := provider.Load(true); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Within the poll interval the fetched commit is served without fetching again
+	repo.commit(map[string]string{"web.yaml": localConfig(5)})
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if resources[0].Provenance.Revision != first {
+		t.Errorf("Load() within poll interval commit = %q, want %q", resources[0].Provenance.Revision, first)
+	}
+}
+
+func TestGitProvider_Load_Errors(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit(map[string]string{"schedules/web.yaml": localConfig(3)})
+
+	tests := []struct {
+		name        string
+		config      GitConfig
+		errContains string
+	}{
+		{
+			name:        "missing ref",
+			config:      GitConfig{URL: repo.dir, Ref: "missing"},
+			errContains: "git fetch failed",
+		},
+		{
+			name:        "missing path",
+			config:      GitConfig{URL: repo.dir, Path: "other"},
+			errContains: `"other" not found in repository`,
+		},
+		{
+			name:        "missing repository",
+			config:      GitConfig{URL: filepath.Join(t.TempDir(), "missing")},
+			errContains: "git fetch failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Dir = t.TempDir()
+			provider, err := NewGitProvider(tt.config)
+			if err != nil {
+				t.Fatalf("NewGitProvider() error = %v", err)
+			}
+			if _, err := provider.Load(true); err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
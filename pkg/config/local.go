@@ -1,18 +1,42 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
 
 	"github.com/berkayuckac/k8schedul8r/pkg/model"
 	"gopkg.in/yaml.v3"
 )
 
+// LocalProvider loads resources from a YAML or JSON file, from every such file under
+// a directory, or from the files matching a glob pattern. The last content that
+// validated is kept, so an invalid edit does not drop the running configuration.
+// While watching, subscribers are notified of each change that validates.
 type LocalProvider struct {
-	path string
+	notifier
+	path    string
+	current *localRevision
+	lastErr error
+	watcher *fsnotify.Watcher
+	mu      sync.RWMutex
+	wg      sync.WaitGroup
+}
+
+// localRevision is a validated parse of the files
+type localRevision struct {
+	resources  []model.Resource
+	generation int64
+	hash       string
 }
 
 func NewLocalProvider(path string) *LocalProvider {
@@ -21,41 +45,374 @@ func NewLocalProvider(path string) *LocalProvider {
 	}
 }
 
+// String describes the provider's source
+func (l *LocalProvider) String() string {
+	return "file " + l.path
+}
+
+// Watch starts watching the file for changes. Once watching, Load serves the last
+// good parse from memory and the file is only re-read when it changes. The file's
+// directory is watched, so editors that replace the file and ConfigMap volumes that
+// swap a symlink are both picked up. For a directory every subdirectory is watched,
+// and for a glob pattern the directories it can match in.
+func (l *LocalProvider) Watch() error {
+	dirs, err := l.watchDirs()
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch config directory %s: %w", dir, err)
+		}
+	}
+
+	l.mu.Lock()
+	if l.watcher != nil {
+		l.mu.Unlock()
+		watcher.Close()
+		return nil
+	}
+	l.watcher = watcher
+	l.mu.Unlock()
+
+	// Resolve the file before loading it, so a swap that lands in between is still seen
+	realPath, _ := filepath.EvalSymlinks(l.path)
+	l.reload()
+
+	l.wg.Add(1)
+	go l.watch(watcher, realPath)
+	return nil
+}
+
+// Stop stops watching the file
+func (l *LocalProvider) Stop() {
+	l.mu.Lock()
+	watcher := l.watcher
+	l.watcher = nil
+	l.mu.Unlock()
+
+	if watcher != nil {
+		watcher.Close()
+		l.wg.Wait()
+	}
+	l.notifier.close()
+}
+
+// watch reloads the file whenever it, or the symlink it resolves through, changes.
+// realPath is where the file resolved to when it was last loaded. Directories and
+// glob patterns are reloaded on any change.
+func (l *LocalProvider) watch(watcher *fsnotify.Watcher, realPath string) {
+	defer l.wg.Done()
+
+	path := filepath.Clean(l.path)
+	source := l.source()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if source != localFile {
+				// Watch new subdirectories so files created in them are picked up
+				if source == localDir && event.Has(fsnotify.Create) && !isHidden(event.Name) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						watcher.Add(event.Name)
+					}
+				}
+				l.reload()
+				continue
+			}
+			// A ConfigMap update swaps a symlink elsewhere in the directory, so also
+			// reload when the file now resolves to a different target
+			newRealPath, _ := filepath.EvalSymlinks(path)
+			if filepath.Clean(event.Name) == path || newRealPath != realPath {
+				realPath = newRealPath
+				l.reload()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			l.mu.Lock()
+			l.lastErr = fmt.Errorf("file watcher failed: %w", err)
+			l.mu.Unlock()
+		}
+	}
+}
+
+// reload reads and validates the file, swapping it in if it differs from the current revision
+func (l *LocalProvider) reload() {
+	resources, hash, err := l.read(true)
+
+	l.mu.Lock()
+	l.lastErr = err
+	changed := err == nil && l.swap(resources, hash)
+	l.mu.Unlock()
+
+	if changed {
+		l.notify()
+	}
+}
+
+// swap makes resources the current revision unless the content is unchanged, and
+// reports whether it did. Callers hold mu.
+func (l *LocalProvider) swap(resources []model.Resource, hash string) bool {
+	if l.current != nil && l.current.hash == hash {
+		return false
+	}
+	var generation int64 = 1
+	if l.current != nil {
+		generation = l.current.generation + 1
+	}
+	l.current = &localRevision{
+		resources:  resources,
+		generation: generation,
+		hash:       hash,
+	}
+	return true
+}
+
+// Revision implements RevisionProvider.Revision
+func (l *LocalProvider) Revision() Revision {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	rev := Revision{Error: l.lastErr}
+	if l.current != nil {
+		rev.Generation = l.current.generation
+		rev.Hash = l.current.hash
+	}
+	return rev
+}
+
 // Load implements Provider.Load
 func (l *LocalProvider) Load(validate bool) ([]model.Resource, error) {
-	data, err := os.ReadFile(l.path)
+	l.mu.RLock()
+	current, watching := l.current, l.watcher != nil
+	l.mu.RUnlock()
+
+	if watching && current != nil {
+		return current.resources, nil
+	}
+
+	resources, hash, err := l.read(validate)
+	if err != nil {
+		// Keep serving the last good configuration when the file turns invalid
+		if validate && current != nil {
+			l.mu.Lock()
+			l.lastErr = err
+			l.mu.Unlock()
+			return current.resources, nil
+		}
+		return nil, err
+	}
+
+	if validate {
+		l.mu.Lock()
+		l.lastErr = nil
+		l.swap(resources, hash)
+		l.mu.Unlock()
+	}
+
+	return resources, nil
+}
+
+// read parses the files and returns their resources and content hash
+func (l *LocalProvider) read(validate bool) ([]model.Resource, string, error) {
+	files, err := l.files()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, "", err
+	}
+
+	hash := sha256.New()
+	allResources := []model.Resource{}
+	loadedAt := time.Now()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, "", l.fileError(file, fmt.Errorf("failed to read config file: %w", err))
+		}
+		hash.Write(data)
+		fileHash := sha256.Sum256(data)
+
+		format, err := formatFromPath(file)
+		if err != nil {
+			return nil, "", l.fileError(file, err)
+		}
+
+		resources, err := decodeConfig(data, format)
+		if err != nil {
+			return nil, "", l.fileError(file, err)
+		}
+
+		if validate {
+			for i := range resources {
+				if err := resources[i].Validate(); err != nil {
+					return nil, "", l.fileError(file, fmt.Errorf("resource[%d] validation failed: %w", i, err))
+				}
+			}
+		}
+
+		for i := range resources {
+			resources[i].Provenance = &model.Provenance{
+				Provider: model.ProviderFile,
+				Source:   file,
+				Revision: hex.EncodeToString(fileHash[:]),
+				LoadedAt: loadedAt,
+			}
+		}
+		allResources = append(allResources, resources...)
+	}
+
+	if validate && len(allResources) == 0 {
+		return nil, "", fmt.Errorf("no resources defined")
 	}
 
-	var resources []model.Resource
-	ext := strings.ToLower(filepath.Ext(l.path))
+	return allResources, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Kinds of path a LocalProvider can be given
+const (
+	localFile = iota
+	localDir
+	localGlob
+)
+
+// source reports whether the provider's path names a file, a directory or a glob pattern.
+// A path that does not exist yet is treated as a file.
+func (l *LocalProvider) source() int {
+	if strings.ContainsAny(l.path, "*?[") {
+		return localGlob
+	}
+	if info, err := os.Stat(l.path); err == nil && info.IsDir() {
+		return localDir
+	}
+	return localFile
+}
 
-	switch ext {
-	case ".yaml", ".yml":
-		err = yaml.Unmarshal(data, &resources)
+// files lists the configuration files to load, in lexical order
+func (l *LocalProvider) files() ([]string, error) {
+	switch l.source() {
+	case localGlob:
+		matches, err := filepath.Glob(l.path)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+			return nil, fmt.Errorf("invalid config pattern %q: %w", l.path, err)
 		}
-	case ".json":
-		err = json.Unmarshal(data, &resources)
+		var files []string
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || info.IsDir() {
+				continue
+			}
+			if _, err := formatFromPath(match); err == nil {
+				files = append(files, match)
+			}
+		}
+		return files, nil
+	case localDir:
+		var files []string
+		err := filepath.WalkDir(l.path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Skip hidden entries such as the ..data directory of a mounted ConfigMap,
+			// whose files are already reachable through their symlinks
+			if path != l.path && isHidden(path) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if _, err := formatFromPath(path); err == nil {
+				files = append(files, path)
+			}
+			return nil
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse JSON config: %w", err)
+			return nil, fmt.Errorf("failed to list config directory: %w", err)
 		}
+		return files, nil
 	default:
-		return nil, fmt.Errorf("unsupported file format: %s", ext)
+		return []string{l.path}, nil
 	}
+}
 
-	if validate {
-		if len(resources) == 0 {
-			return nil, fmt.Errorf("no resources defined")
+// watchDirs lists the directories to watch for changes to the configuration files
+func (l *LocalProvider) watchDirs() ([]string, error) {
+	switch l.source() {
+	case localGlob:
+		dirs, err := filepath.Glob(filepath.Dir(l.path))
+		if err != nil {
+			return nil, fmt.Errorf("invalid config pattern %q: %w", l.path, err)
 		}
-		for i, res := range resources {
-			if err := res.Validate(); err != nil {
-				return nil, fmt.Errorf("resource[%d] validation failed: %w", i, err)
+		return dirs, nil
+	case localDir:
+		var dirs []string
+		err := filepath.WalkDir(l.path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
 			}
+			if path != l.path && isHidden(path) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list config directory: %w", err)
+		}
+		return dirs, nil
+	default:
+		return []string{filepath.Dir(l.path)}, nil
+	}
+}
+
+// fileError attributes err to file when the provider loads more than one file
+func (l *LocalProvider) fileError(file string, err error) error {
+	switch l.source() {
+	case localFile:
+		return err
+	case localDir:
+		if rel, relErr := filepath.Rel(l.path, file); relErr == nil {
+			file = rel
 		}
 	}
+	return fmt.Errorf("%s: %w", file, err)
+}
 
-	return resources, nil
+// isHidden reports whether the last element of path starts with a dot
+func isHidden(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), ".")
+}
+
+// unmarshalFile decodes data into out based on the extension of path
+func unmarshalFile(path string, data []byte, out interface{}) error {
+	format, err := formatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse YAML config: %w", err)
+		}
+	case FormatJSON:
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse JSON config: %w", err)
+		}
+	}
+	return nil
 }
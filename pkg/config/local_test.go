@@ -3,9 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
 )
 
 func TestLocalProvider_Load(t *testing.T) {
@@ -257,3 +260,317 @@ func TestLocalProvider_Load_InvalidYAML(t *testing.T) {
 		t.Error("expected error for invalid YAML, got nil")
 	}
 }
+
+func TestLocalProvider_Load_RecurringWindow(t *testing.T) {
+	tmpfile, err := os.CreateTemp("", "test-config-*.yaml")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	recurringYAML := `- name: test-scaler
+  namespace: default
+  target:
+    name: test-deployment
+    kind: Deployment
+  originalReplicas: 2
+  windows:
+    - days: ["Mon-Fri"]
+      startTimeOfDay: "08:00"
+      endTimeOfDay: "18:00"
+      replicas: 4`
+
+	if err := os.WriteFile(tmpfile.Name(), []byte(recurringYAML), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	provider := NewLocalProvider(tmpfile.Name())
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 1 || len(resources[0].Windows) != 1 {
+		t.Fatalf("expected 1 resource with 1 window, got %+v", resources)
+	}
+
+	window := resources[0].Windows[0]
+	if len(window.Days) != 1 || window.Days[0] != "Mon-Fri" {
+		t.Errorf("expected days [Mon-Fri], got %v", window.Days)
+	}
+	if window.StartTimeOfDay != "08:00" || window.EndTimeOfDay != "18:00" {
+		t.Errorf("expected 08:00-18:00, got %s-%s", window.StartTimeOfDay, window.EndTimeOfDay)
+	}
+}
+
+// localConfig returns a one-resource YAML configuration whose window scales to replicas
+func localConfig(replicas int) string {
+	return fmt.Sprintf(`- name: test-scaler
+  namespace: default
+  target:
+    name: test-deployment
+    kind: Deployment
+  originalReplicas: 2
+  windows:
+    - days: ["Mon-Fri"]
+      startTimeOfDay: "08:00"
+      endTimeOfDay: "18:00"
+      replicas: %d`, replicas)
+}
+
+// waitForGeneration waits until the provider serves the given generation
+func waitForGeneration(t *testing.T, provider *LocalProvider, generation int64) Revision {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rev := provider.Revision()
+		if rev.Generation == generation {
+			return rev
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("generation = %d, want %d", rev.Generation, generation)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLocalProvider_Load_KeepsLastGood(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(localConfig(3)), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	provider := NewLocalProvider(path)
+	if _, err := provider.Load(true); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	first := provider.Revision()
+	if first.Generation != 1 || first.Hash == "" {
+		t.Fatalf("Revision() = %+v, want generation 1 with a hash", first)
+	}
+
+	// Unchanged content keeps the generation
+	if _, err := provider.Load(true); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if rev := provider.Revision(); rev.Generation != 1 {
+		t.Errorf("generation after reloading unchanged file = %d, want 1", rev.Generation)
+	}
+
+	// Invalid content is reported but the last good resources are served
+	if err := os.WriteFile(path, []byte(localConfig(-1)), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() with invalid file error = %v", err)
+	}
+	if len(resources) != 1 || resources[0].Windows[0].Replicas != 3 {
+		t.Errorf("Load() with invalid file = %+v, want the last good resources", resources)
+	}
+	if rev := provider.Revision(); rev.Generation != 1 || rev.Error == nil {
+		t.Errorf("Revision() = %+v, want generation 1 with an error", rev)
+	}
+}
+
+func TestLocalProvider_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(localConfig(3)), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	provider := NewLocalProvider(path)
+	if err := provider.Watch(); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer provider.Stop()
+	waitForGeneration(t, provider, 1)
+
+	// An invalid edit is ignored
+	if err := os.WriteFile(path, []byte("- name: [unclosed"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for provider.Revision().Error == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("invalid edit was not reported")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	resources, err := provider.Load(true)
+	if err != nil || len(resources) != 1 || resources[0].Windows[0].Replicas != 3 {
+		t.Fatalf("Load() after invalid edit = %+v, %v, want the last good resources", resources, err)
+	}
+
+	// A valid edit is swapped in and notified
+	changes := provider.Subscribe()
+	if err := os.WriteFile(path, []byte(localConfig(5)), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	waitForChange(t, changes)
+	if rev := waitForGeneration(t, provider, 2); rev.Error != nil {
+		t.Errorf("Revision().Error = %v, want nil", rev.Error)
+	}
+	resources, err = provider.Load(true)
+	if err != nil || len(resources) != 1 || resources[0].Windows[0].Replicas != 5 {
+		t.Errorf("Load() after edit = %+v, %v, want 5 replicas", resources, err)
+	}
+}
+
+func TestLocalProvider_WatchSymlinkSwap(t *testing.T) {
+	// Lay the directory out like a ConfigMap volume:
+	// config.yaml -> ..data/config.yaml, ..data -> ..v1
+	dir := t.TempDir()
+	writeVersion := func(version string, replicas int) {
+		t.Helper()
+		if err := os.Mkdir(filepath.Join(dir, version), 0755); err != nil {
+			t.Fatalf("failed to create version dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version, "config.yaml"), []byte(localConfig(replicas)), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+	writeVersion("..v1", 3)
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Fatalf("failed to create data symlink: %v", err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.Symlink(filepath.Join("..data", "config.yaml"), path); err != nil {
+		t.Fatalf("failed to create config symlink: %v", err)
+	}
+
+	provider := NewLocalProvider(path)
+	if err := provider.Watch(); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer provider.Stop()
+	waitForGeneration(t, provider, 1)
+
+	// Swap ..data atomically, as the kubelet does
+	writeVersion("..v2", 7)
+	tmpLink := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink("..v2", tmpLink); err != nil {
+		t.Fatalf("failed to create temporary symlink: %v", err)
+	}
+	if err := os.Rename(tmpLink, filepath.Join(dir, "..data")); err != nil {
+		t.Fatalf("failed to swap data symlink: %v", err)
+	}
+
+	waitForGeneration(t, provider, 2)
+	resources, err := provider.Load(true)
+	if err != nil || len(resources) != 1 || resources[0].Windows[0].Replicas != 7 {
+		t.Errorf("Load() after swap = %+v, %v, want 7 replicas", resources, err)
+	}
+}
+
+// writeFiles writes files, keyed by their path relative to dir, creating directories as needed
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestLocalProvider_Load_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"teams/payments.yaml": strings.Replace(localConfig(3), "test-scaler", "payments", 1) +
+			"\n---\n" + strings.Replace(localConfig(4), "test-scaler", "payments-worker", 1),
+		"teams/search.json": `{"version": "1", "defaults": {"namespace": "search"}, "resources": [{"name": "search", "target": {"name": "search", "kind": "Deployment"}, "originalReplicas": 1, "windows": []}]}`,
+		"base.yml":          strings.Replace(localConfig(2), "test-scaler", "base", 1),
+		"README.md":         "not a config file",
+		"..data/stale.yaml": "- name: [unclosed",
+		".hidden.yaml":      "- name: [unclosed",
+	})
+
+	resources, err := NewLocalProvider(dir).Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var names []string
+	for _, res := range resources {
+		names = append(names, res.Name)
+	}
+	want := "base,payments,payments-worker,search"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("Load() resources = %s, want %s", got, want)
+	}
+	if resources[3].Namespace != "search" {
+		t.Errorf("search namespace = %q, want the document default", resources[3].Namespace)
+	}
+	// Each resource records the file it came from
+	if p := resources[2].Provenance; p == nil || p.Provider != model.ProviderFile ||
+		p.Source != filepath.Join(dir, "teams", "payments.yaml") || len(p.Revision) != 64 {
+		t.Errorf("payments-worker provenance = %+v, want teams/payments.yaml with its content hash", p)
+	}
+
+	// Errors name the file they come from
+	writeFiles(t, dir, map[string]string{
+		"teams/payments.yaml": localConfig(3) + "\n---\n" + localConfig(-1),
+	})
+	_, err = NewLocalProvider(dir).Load(true)
+	if err == nil || !strings.Contains(err.Error(), filepath.Join("teams", "payments.yaml")+": resource[1] validation failed") {
+		t.Errorf("Load() error = %v, want it attributed to teams/payments.yaml", err)
+	}
+}
+
+func TestLocalProvider_Load_Glob(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"teams/a.yaml":    strings.Replace(localConfig(3), "test-scaler", "a", 1),
+		"teams/b.yaml":    strings.Replace(localConfig(4), "test-scaler", "b", 1),
+		"teams/c.json":    "not matched",
+		"other/d.yaml":    "not matched",
+		"teams/sub/e.yml": "not matched",
+	})
+
+	pattern := filepath.Join(dir, "teams", "*.yaml")
+	resources, err := NewLocalProvider(pattern).Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(resources) != 2 || resources[0].Name != "a" || resources[1].Name != "b" {
+		t.Errorf("Load() = %+v, want resources a and b", resources)
+	}
+
+	writeFiles(t, dir, map[string]string{"teams/b.yaml": "- name: [unclosed"})
+	_, err = NewLocalProvider(pattern).Load(true)
+	if err == nil || !strings.Contains(err.Error(), filepath.Join(dir, "teams", "b.yaml")+": failed to parse YAML config") {
+		t.Errorf("Load() error = %v, want it attributed to b.yaml", err)
+	}
+
+	if _, err := NewLocalProvider(filepath.Join(dir, "none", "*.yaml")).Load(true); err == nil || !strings.Contains(err.Error(), "no resources defined") {
+		t.Errorf("Load() with no matches error = %v, want no resources defined", err)
+	}
+}
+
+func TestLocalProvider_WatchDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"base.yaml": localConfig(3)})
+
+	provider := NewLocalProvider(dir)
+	if err := provider.Watch(); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	defer provider.Stop()
+	waitForGeneration(t, provider, 1)
+
+	// A file added in a new subdirectory is picked up
+	if err := os.Mkdir(filepath.Join(dir, "teams"), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	writeFiles(t, dir, map[string]string{
+		"teams/payments.yaml": strings.Replace(localConfig(5), "test-scaler", "payments", 1),
+	})
+	waitForGeneration(t, provider, 2)
+
+	resources, err := provider.Load(true)
+	if err != nil || len(resources) != 2 || resources[1].Name != "payments" {
+		t.Errorf("Load() after adding a file = %+v, %v, want the payments resource", resources, err)
+	}
+}
@@ -2,31 +2,405 @@ package config
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/berkayuckac/k8schedul8r/pkg/model"
 )
 
+// Conflict policies decide what happens when resources from different providers
+// target the same workload
+const (
+	// ConflictPolicyPrecedence keeps the resources of the provider listed first (the default)
+	ConflictPolicyPrecedence = "precedence"
+	// ConflictPolicyReject drops every resource targeting the workload, leaving it unscaled
+	ConflictPolicyReject = "reject"
+	// ConflictPolicyMerge keeps the first resource and appends the windows of the others.
+	// Resources whose windows would be evaluated differently, such as in another time
+	// zone or scale mode, are rejected instead.
+	ConflictPolicyMerge = "merge"
+)
+
+// MultiProviderConfig holds the configuration for combining providers
+type MultiProviderConfig struct {
+	// ConflictPolicy is "precedence" (default), "reject" or "merge"
+	ConflictPolicy string `json:"conflictPolicy" yaml:"conflictPolicy"`
+}
+
+// Conflict describes resources from different providers that target the same workload
+type Conflict struct {
+	// Target is the workload as namespace/kind/name
+	Target string
+	// Sources name the providers whose resources target it, in precedence order
+	Sources []string
+	// Policy is the conflict policy applied
+	Policy string
+	// Reason explains why a policy other than the configured one was applied
+	Reason string
+}
+
+func (c Conflict) String() string {
+	sources := strings.Join(c.Sources, " and ")
+	switch c.Policy {
+	case ConflictPolicyReject:
+		if c.Reason != "" {
+			return fmt.Sprintf("%s is targeted by %s, not scaling it: %s", c.Target, sources, c.Reason)
+		}
+		return fmt.Sprintf("%s is targeted by %s, not scaling it", c.Target, sources)
+	case ConflictPolicyMerge:
+		return fmt.Sprintf("%s is targeted by %s, merging their windows", c.Target, sources)
+	default:
+		return fmt.Sprintf("%s is targeted by %s, using %s", c.Target, sources, c.Sources[0])
+	}
+}
+
+// ProviderError is the failure of one provider of a MultiProvider
+type ProviderError struct {
+	// Source names the provider
+	Source string
+	Err    error
+}
+
+func (e *ProviderError) Error() string {
+	return e.Source + ": " + e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError aggregates the failures of a MultiProvider's providers
+type MultiError struct {
+	// Errors holds one error per failed provider, in precedence order
+	Errors []*ProviderError
+	// Providers is the number of providers loaded
+	Providers int
+}
+
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d configuration providers failed: %s",
+		len(e.Errors), e.Providers, strings.Join(messages, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Partial reports whether some providers loaded, so their resources can still apply
+func (e *MultiError) Partial() bool {
+	return len(e.Errors) < e.Providers
+}
+
+// MultiProvider combines the resources of several providers, resolving resources
+// from different providers that target the same workload under its conflict policy.
+// Providers are listed in precedence order. A failing provider does not keep the
+// others' resources from loading, but the workloads it targeted when it last loaded
+// stay part of conflict resolution, so its failure does not hand them to another
+// provider. Subscribers are notified of the changes of every provider implementing
+// WatchProvider. Stop stops every provider implementing StopProvider.
 type MultiProvider struct {
+	notifier
 	providers []Provider
+	policy    string
+	conflicts []Conflict
+	// health tracks each provider's loads, by provider index
+	health []Health
+	// targets holds the targets of each provider's last successful load, by provider index
+	targets []map[string]bool
+	mu      sync.RWMutex
+	// subscriptions holds the channel each WatchProvider notifies, by provider index
+	subscriptions map[int]<-chan struct{}
+	forwarding    sync.WaitGroup
+	stopOnce      sync.Once
+}
+
+func NewMultiProvider(config MultiProviderConfig, providers ...Provider) (*MultiProvider, error) {
+	policy := config.ConflictPolicy
+	switch policy {
+	case "":
+		policy = ConflictPolicyPrecedence
+	case ConflictPolicyPrecedence, ConflictPolicyReject, ConflictPolicyMerge:
+	default:
+		return nil, fmt.Errorf("unknown conflict policy %q, expected %q, %q or %q",
+			policy, ConflictPolicyPrecedence, ConflictPolicyReject, ConflictPolicyMerge)
+	}
+
+	m := &MultiProvider{
+		providers:     providers,
+		policy:        policy,
+		health:        make([]Health, len(providers)),
+		targets:       make([]map[string]bool, len(providers)),
+		subscriptions: make(map[int]<-chan struct{}),
+	}
+	for i, provider := range providers {
+		if watcher, ok := provider.(WatchProvider); ok {
+			changes := watcher.Subscribe()
+			m.subscriptions[i] = changes
+			m.forwarding.Add(1)
+			go m.forward(changes)
+		}
+	}
+	return m, nil
+}
+
+// forward passes a provider's change notifications on until the provider stops or
+// the subscription ends
+func (m *MultiProvider) forward(changes <-chan struct{}) {
+	defer m.forwarding.Done()
+
+	for range changes {
+		m.notify()
+	}
+}
+
+// Stop implements StopProvider.Stop. It ends the subscriptions to the providers'
+// changes, stops every provider implementing StopProvider and closes the channels
+// returned by Subscribe.
+func (m *MultiProvider) Stop() {
+	m.stopOnce.Do(func() {
+		for i, changes := range m.subscriptions {
+			m.providers[i].(WatchProvider).Unsubscribe(changes)
+		}
+		m.forwarding.Wait()
+
+		for _, provider := range m.providers {
+			if stopper, ok := provider.(StopProvider); ok {
+				stopper.Stop()
+			}
+		}
+		m.notifier.close()
+	})
+}
+
+// SourceHealth implements SourceHealthProvider.SourceHealth. Providers that report
+// their own health do so, the others are judged by the outcome of their loads.
+func (m *MultiProvider) SourceHealth() []SourceHealth {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sources := make([]SourceHealth, len(m.providers))
+	for i, provider := range m.providers {
+		health := m.health[i]
+		if healthProvider, ok := provider.(HealthProvider); ok {
+			health = healthProvider.Health()
+		}
+		sources[i] = SourceHealth{Source: sourceName(provider), Health: health}
+	}
+	return sources
 }
 
-func NewMultiProvider(providers ...Provider) *MultiProvider {
-	return &MultiProvider{
-		providers: providers,
+// recordLoad updates a provider's health with the outcome of a load
+func (m *MultiProvider) recordLoad(i int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err == nil {
+		m.health[i] = Health{LastSuccess: time.Now()}
+		return
 	}
+	m.health[i].LastError = err
+	m.health[i].ConsecutiveFailures++
 }
 
-// Load implements Provider interface
+// Conflicts implements ConflictProvider.Conflicts
+func (m *MultiProvider) Conflicts() []Conflict {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.conflicts
+}
+
+// Load implements Provider interface. When providers fail, the error is a *MultiError
+// naming each of them; if only some failed, the resources of the others are returned
+// along with it.
 func (m *MultiProvider) Load(validate bool) ([]model.Resource, error) {
 	var allResources []model.Resource
+	var sources []int
+	var errs []*ProviderError
+	// reserved maps the targets of failed providers' last successful loads to them
+	reserved := make(map[string][]int)
 
-	for _, provider := range m.providers {
+	for i, provider := range m.providers {
 		resources, err := provider.Load(validate)
+		m.recordLoad(i, err)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load from provider: %w", err)
+			errs = append(errs, &ProviderError{Source: sourceName(provider), Err: err})
+			m.mu.RLock()
+			for key := range m.targets[i] {
+				reserved[key] = append(reserved[key], i)
+			}
+			m.mu.RUnlock()
+			continue
 		}
 		allResources = append(allResources, resources...)
+		targets := make(map[string]bool, len(resources))
+		for j := range resources {
+			sources = append(sources, i)
+			targets[targetKey(&resources[j])] = true
+		}
+		m.mu.Lock()
+		m.targets[i] = targets
+		m.mu.Unlock()
+	}
+
+	resolved, conflicts := m.resolve(allResources, sources, reserved)
+
+	m.mu.Lock()
+	m.conflicts = conflicts
+	m.mu.Unlock()
+
+	if len(errs) > 0 {
+		multiErr := &MultiError{Errors: errs, Providers: len(m.providers)}
+		if !multiErr.Partial() {
+			return nil, multiErr
+		}
+		return resolved, multiErr
+	}
+	return resolved, nil
+}
+
+// resolve applies the conflict policy to resources, where sources holds the index of
+// the provider each resource came from and reserved the failed providers still
+// holding each target. Resources are grouped by target in the order their targets
+// first appear.
+func (m *MultiProvider) resolve(resources []model.Resource, sources []int, reserved map[string][]int) ([]model.Resource, []Conflict) {
+	var targets []string
+	byTarget := make(map[string][]int)
+	for i := range resources {
+		key := targetKey(&resources[i])
+		if _, ok := byTarget[key]; !ok {
+			targets = append(targets, key)
+		}
+		byTarget[key] = append(byTarget[key], i)
+	}
+
+	var resolved []model.Resource
+	var conflicts []Conflict
+	for _, key := range targets {
+		indexes := byTarget[key]
+
+		var providers []int
+		for _, i := range indexes {
+			if len(providers) == 0 || providers[len(providers)-1] != sources[i] {
+				providers = append(providers, sources[i])
+			}
+		}
+		failed := reserved[key]
+		// Only resources from different providers conflict
+		if len(providers)+len(failed) < 2 {
+			for _, i := range indexes {
+				resolved = append(resolved, resources[i])
+			}
+			continue
+		}
+
+		participants := append(append([]int(nil), providers...), failed...)
+		sort.Ints(participants)
+		conflict := Conflict{Target: key, Policy: m.policy}
+		for _, p := range participants {
+			conflict.Sources = append(conflict.Sources, sourceName(m.providers[p]))
+		}
+		// A failed provider's resources are unknown, so they can neither take
+		// precedence nor be merged; the workload waits until the provider loads
+		if len(failed) > 0 && (m.policy == ConflictPolicyMerge || participants[0] == failed[0]) {
+			conflict.Policy = ConflictPolicyReject
+			conflict.Reason = sourceName(m.providers[failed[0]]) + " failed to load"
+		}
+		if conflict.Policy == ConflictPolicyMerge {
+			for _, i := range indexes[1:] {
+				if reason := mergeMismatch(&resources[indexes[0]], &resources[i]); reason != "" {
+					conflict.Policy = ConflictPolicyReject
+					conflict.Reason = "cannot merge their windows, " + reason
+					break
+				}
+			}
+		}
+		conflicts = append(conflicts, conflict)
+
+		switch conflict.Policy {
+		case ConflictPolicyReject:
+		case ConflictPolicyMerge:
+			merged := resources[indexes[0]]
+			// Copy the windows so the provider's own resource is not modified
+			merged.Windows = append([]model.ScalingWindow(nil), merged.Windows...)
+			for _, i := range indexes[1:] {
+				merged.Windows = append(merged.Windows, resources[i].Windows...)
+			}
+			// Windows from different providers can overlap each other
+			merged.Warnings = merged.OverlapWarnings(time.Now().Unix())
+			resolved = append(resolved, merged)
+		default:
+			for _, i := range indexes {
+				if sources[i] == providers[0] {
+					resolved = append(resolved, resources[i])
+				}
+			}
+		}
+	}
+
+	return resolved, conflicts
+}
+
+// mergeMismatch describes the first setting that decides how windows apply and
+// differs between two resources, or returns "" when their windows can be merged
+func mergeMismatch(a, b *model.Resource) string {
+	fields := []struct {
+		name   string
+		values [2]string
+	}{
+		{"target apiVersion", [2]string{a.Target.APIVersion, b.Target.APIVersion}},
+		{"timeZone", [2]string{orDefault(a.TimeZone, "UTC"), orDefault(b.TimeZone, "UTC")}},
+		{"originalReplicas", [2]string{fmt.Sprint(a.OriginalReplicas), fmt.Sprint(b.OriginalReplicas)}},
+		{"captureBaseline", [2]string{fmt.Sprint(a.CaptureBaseline), fmt.Sprint(b.CaptureBaseline)}},
+		{"scaleMode", [2]string{orDefault(a.ScaleMode, model.ScaleModeReplicas), orDefault(b.ScaleMode, model.ScaleModeReplicas)}},
+		{"hpaName", [2]string{a.HPAName, b.HPAName}},
+		{"overlapPolicy", [2]string{orDefault(a.OverlapPolicy, model.OverlapPolicyFirst), orDefault(b.OverlapPolicy, model.OverlapPolicyFirst)}},
+		{"calendar", [2]string{a.Calendar, b.Calendar}},
+		{"holidayReplicas", [2]string{optionalReplicas(a.HolidayReplicas), optionalReplicas(b.HolidayReplicas)}},
+	}
+	for _, field := range fields {
+		if field.values[0] != field.values[1] {
+			return fmt.Sprintf("%s %q differs from %q", field.name, field.values[1], field.values[0])
+		}
+	}
+	return ""
+}
+
+// orDefault returns value, or def when value is empty
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
 	}
+	return value
+}
 
-	return allResources, nil
+// optionalReplicas formats an optional replica count, empty when unset
+func optionalReplicas(replicas *int32) string {
+	if replicas == nil {
+		return ""
+	}
+	return fmt.Sprint(*replicas)
+}
+
+// targetKey identifies the workload a resource scales
+func targetKey(res *model.Resource) string {
+	return fmt.Sprintf("%s/%s/%s", res.Namespace, res.Target.Kind, res.Target.Name)
+}
+
+// sourceName describes a provider in warnings
+func sourceName(provider Provider) string {
+	if stringer, ok := provider.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", provider)
 }
@@ -0,0 +1,385 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
+
+// staticProvider serves fixed resources under a name
+type staticProvider struct {
+	name      string
+	resources []model.Resource
+	err       error
+}
+
+func (s *staticProvider) Load(validate bool) ([]model.Resource, error) {
+	return s.resources, s.err
+}
+
+func (s *staticProvider) String() string {
+	return s.name
+}
+
+// multiTestResource returns a resource scaling the web Deployment in namespace with one window
+func multiTestResource(name, namespace string, replicas int32) model.Resource {
+	return model.Resource{
+		Name:             name,
+		Namespace:        namespace,
+		Target:           model.Target{Name: "web", Kind: "Deployment"},
+		OriginalReplicas: 1,
+		Windows:          []model.ScalingWindow{{Days: []string{"Mon-Fri"}, StartTimeOfDay: "08:00", EndTimeOfDay: "18:00", Replicas: replicas}},
+	}
+}
+
+func TestNewMultiProvider(t *testing.T) {
+	if _, err := NewMultiProvider(MultiProviderConfig{ConflictPolicy: "newest"}); err == nil || !strings.Contains(err.Error(), `unknown conflict policy "newest"`) {
+		t.Errorf("NewMultiProvider() error = %v, want unknown conflict policy", err)
+	}
+	provider, err := NewMultiProvider(MultiProviderConfig{})
+	if err != nil {
+		t.Fatalf("NewMultiProvider() error = %v", err)
+	}
+	if provider.policy != ConflictPolicyPrecedence {
+		t.Errorf("NewMultiProvider() policy = %q, want %q", provider.policy, ConflictPolicyPrecedence)
+	}
+}
+
+func TestMultiProvider_Load_Conflicts(t *testing.T) {
+	crd := &staticProvider{name: "ScheduledResources in namespace apps", resources: []model.Resource{
+		multiTestResource("web-crd", "apps", 3),
+		multiTestResource("web-other-namespace", "batch", 2),
+	}}
+	remote := &staticProvider{name: "remote https://config/schedules.yaml", resources: []model.Resource{
+		multiTestResource("web-remote", "apps", 5),
+	}}
+
+	tests := []struct {
+		policy       string
+		wantNames    []string
+		wantReplicas [][]int32
+		wantMessage  string
+		// wantWarnings is the number of warnings on the resource for the web Deployment
+		wantWarnings int
+	}{
+		{
+			policy:       ConflictPolicyPrecedence,
+			wantNames:    []string{"web-crd", "web-other-namespace"},
+			wantReplicas: [][]int32{{3}, {2}},
+			wantMessage: "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+				"remote https://config/schedules.yaml, using ScheduledResources in namespace apps",
+		},
+		{
+			policy:       ConflictPolicyReject,
+			wantNames:    []string{"web-other-namespace"},
+			wantReplicas: [][]int32{{2}},
+			wantMessage: "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+				"remote https://config/schedules.yaml, not scaling it",
+		},
+		{
+			policy:       ConflictPolicyMerge,
+			wantNames:    []string{"web-crd", "web-other-namespace"},
+			wantReplicas: [][]int32{{3, 5}, {2}},
+			wantMessage: "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+				"remote https://config/schedules.yaml, merging their windows",
+			// The merged windows overlap without an overlap policy
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			provider, err := NewMultiProvider(MultiProviderConfig{ConflictPolicy: tt.policy}, crd, remote)
+			if err != nil {
+				t.Fatalf("NewMultiProvider() error = %v", err)
+			}
+
+			resources, err := provider.Load(true)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			var names []string
+			var replicas [][]int32
+			for _, res := range resources {
+				names = append(names, res.Name)
+				var windows []int32
+				for _, window := range res.Windows {
+					windows = append(windows, window.Replicas)
+				}
+				replicas = append(replicas, windows)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) || fmt.Sprint(replicas) != fmt.Sprint(tt.wantReplicas) {
+				t.Errorf("Load() = %v with windows %v, want %v with windows %v", names, replicas, tt.wantNames, tt.wantReplicas)
+			}
+
+			conflicts := provider.Conflicts()
+			if len(conflicts) != 1 || conflicts[0].String() != tt.wantMessage {
+				t.Errorf("Conflicts() = %v, want [%s]", conflicts, tt.wantMessage)
+			}
+			for _, res := range resources {
+				if res.Namespace == "apps" && len(res.Warnings) != tt.wantWarnings {
+					t.Errorf("Load() warnings for %s = %v, want %d", res.Name, res.Warnings, tt.wantWarnings)
+				}
+			}
+		})
+	}
+
+	// Merging does not modify the resources the provider serves
+	if windows := crd.resources[0].Windows; len(windows) != 1 {
+		t.Errorf("merge modified the provider's resource, windows = %+v", windows)
+	}
+}
+
+func TestMultiProvider_Load_MergeMismatch(t *testing.T) {
+	berlin := multiTestResource("web-berlin", "apps", 5)
+	berlin.TimeZone = "Europe/Berlin"
+	crd := &staticProvider{name: "ScheduledResources in namespace apps", resources: []model.Resource{
+		multiTestResource("web-crd", "apps", 3),
+	}}
+	remote := &staticProvider{name: "remote https://config/schedules.yaml", resources: []model.Resource{berlin}}
+
+	provider, err := NewMultiProvider(MultiProviderConfig{ConflictPolicy: ConflictPolicyMerge}, crd, remote)
+	if err != nil {
+		t.Fatalf("NewMultiProvider() error = %v", err)
+	}
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Windows written for another time zone are not merged, the workload is left unscaled
+	if len(resources) != 0 {
+		t.Errorf("Load() = %+v, want no resources", resources)
+	}
+	want := "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+		"remote https://config/schedules.yaml, not scaling it: cannot merge their windows, " +
+		`timeZone "Europe/Berlin" differs from "UTC"`
+	conflicts := provider.Conflicts()
+	if len(conflicts) != 1 || conflicts[0].Policy != ConflictPolicyReject || conflicts[0].String() != want {
+		t.Errorf("Conflicts() = %v, want [%s]", conflicts, want)
+	}
+}
+
+func TestMultiProvider_Load_NoConflicts(t *testing.T) {
+	// Resources of one provider targeting the same workload are not a conflict between providers
+	local := &staticProvider{name: "file /etc/config", resources: []model.Resource{
+		multiTestResource("web-weekdays", "apps", 3),
+		multiTestResource("web-weekends", "apps", 1),
+	}}
+	crd := &staticProvider{name: "ScheduledResources in namespace batch", resources: []model.Resource{
+		multiTestResource("web", "batch", 2),
+	}}
+
+	provider, err := NewMultiProvider(MultiProviderConfig{ConflictPolicy: ConflictPolicyReject}, local, crd)
+	if err != nil {
+		t.Fatalf("NewMultiProvider() error = %v", err)
+	}
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(resources) != 3 {
+		t.Errorf("Load() returned %d resources, want 3", len(resources))
+	}
+	if conflicts := provider.Conflicts(); len(conflicts) != 0 {
+		t.Errorf("Conflicts() = %v, want none", conflicts)
+	}
+}
+
+func TestMultiProvider_Load_PartialFailure(t *testing.T) {
+	crd := &staticProvider{name: "ScheduledResources in namespace apps", resources: []model.Resource{
+		multiTestResource("web", "apps", 3),
+	}}
+	remote := &staticProvider{name: "remote https://config", err: fmt.Errorf("connection refused")}
+
+	provider, err := NewMultiProvider(MultiProviderConfig{}, remote, crd)
+	if err != nil {
+		t.Fatalf("NewMultiProvider() error = %v", err)
+	}
+
+	// The healthy provider's resources load alongside the failure
+	resources, err := provider.Load(true)
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) || !multiErr.Partial() {
+		t.Fatalf("Load() error = %v, want a partial *MultiError", err)
+	}
+	if want := "1 of 2 configuration providers failed: remote https://config: connection refused"; err.Error() != want {
+		t.Errorf("Load() error = %q, want %q", err, want)
+	}
+	if len(resources) != 1 || resources[0].Name != "web" {
+		t.Errorf("Load() = %+v, want the CRD resource", resources)
+	}
+
+	health := provider.SourceHealth()
+	if len(health) != 2 || health[0].ConsecutiveFailures != 1 || health[0].LastError == nil {
+		t.Errorf("SourceHealth() = %+v, want the remote provider failing once", health)
+	}
+	if health[1].LastError != nil || health[1].LastSuccess.IsZero() {
+		t.Errorf("SourceHealth() = %+v, want the CRD provider healthy", health)
+	}
+
+	// With every provider failing nothing loads
+	crd.err = fmt.Errorf("cache not synced")
+	resources, err = provider.Load(true)
+	if !errors.As(err, &multiErr) || multiErr.Partial() || len(multiErr.Errors) != 2 {
+		t.Fatalf("Load() error = %v, want a *MultiError for both providers", err)
+	}
+	if resources != nil {
+		t.Errorf("Load() = %+v, want nil", resources)
+	}
+	if health := provider.SourceHealth(); health[0].ConsecutiveFailures != 2 || health[1].ConsecutiveFailures != 1 {
+		t.Errorf("SourceHealth() = %+v, want 2 and 1 consecutive failures", health)
+	}
+}
+
+func TestMultiProvider_Subscribe(t *testing.T) {
+	crd, err := NewCRDProvider(CRDConfig{Namespace: "apps"}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewCRDProvider() error = %v", err)
+	}
+	remote := &staticProvider{name: "remote https://config"}
+
+	provider, err := NewMultiProvider(MultiProviderConfig{}, remote, crd)
+	if err != nil {
+		t.Fatalf("NewMultiProvider() error = %v", err)
+	}
+	changes := provider.Subscribe()
+
+	// Changes of the providers that push them are passed on
+	crd.UpdateResource(multiTestResource("web", "apps", 3))
+	waitForChange(t, changes)
+}
+
+func TestMultiProvider_Stop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(localConfig(3)), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	local := NewLocalProvider(path)
+	if err := local.Watch(); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	crd, err := NewCRDProvider(CRDConfig{Namespace: "apps"}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewCRDProvider() error = %v", err)
+	}
+
+	provider, err := NewMultiProvider(MultiProviderConfig{}, local, crd)
+	if err != nil {
+		t.Fatalf("NewMultiProvider() error = %v", err)
+	}
+	localChanges := local.Subscribe()
+	changes := provider.Subscribe()
+
+	provider.Stop()
+	// Stopping again is harmless
+	provider.Stop()
+
+	// The local provider stopped watching, closing its subscribers' channels. A change
+	// notified before Stop may still be pending.
+	for name, ch := range map[string]<-chan struct{}{"local provider": localChanges, "multi provider": changes} {
+		timeout := time.After(5 * time.Second)
+	drain:
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					break drain
+				}
+			case <-timeout:
+				t.Errorf("Stop() left the %s's channel open", name)
+				break drain
+			}
+		}
+	}
+
+	// The CRD provider, which has nothing to stop, is no longer forwarded
+	crd.UpdateResource(multiTestResource("web", "apps", 3))
+	crd.notifier.mu.Lock()
+	subscribers := len(crd.notifier.subscribers)
+	crd.notifier.mu.Unlock()
+	if subscribers != 0 {
+		t.Errorf("CRD provider has %d subscribers after Stop(), want 0", subscribers)
+	}
+}
+
+func TestMultiProvider_Load_FailedProviderKeepsTargets(t *testing.T) {
+	tests := []struct {
+		policy string
+		// failing is the provider failing on the second load, 0 for the CRD provider
+		// listed first or 1 for the remote provider
+		failing     int
+		wantNames   []string
+		wantMessage string
+	}{
+		{
+			policy:    ConflictPolicyPrecedence,
+			failing:   0,
+			wantNames: nil,
+			wantMessage: "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+				"remote https://config, not scaling it: ScheduledResources in namespace apps failed to load",
+		},
+		{
+			policy:    ConflictPolicyPrecedence,
+			failing:   1,
+			wantNames: []string{"web-crd"},
+			wantMessage: "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+				"remote https://config, using ScheduledResources in namespace apps",
+		},
+		{
+			policy:    ConflictPolicyReject,
+			failing:   0,
+			wantNames: nil,
+			wantMessage: "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+				"remote https://config, not scaling it: ScheduledResources in namespace apps failed to load",
+		},
+		{
+			policy:    ConflictPolicyMerge,
+			failing:   1,
+			wantNames: nil,
+			wantMessage: "apps/Deployment/web is targeted by ScheduledResources in namespace apps and " +
+				"remote https://config, not scaling it: remote https://config failed to load",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s with provider %d failing", tt.policy, tt.failing), func(t *testing.T) {
+			providers := []*staticProvider{
+				{name: "ScheduledResources in namespace apps", resources: []model.Resource{multiTestResource("web-crd", "apps", 3)}},
+				{name: "remote https://config", resources: []model.Resource{multiTestResource("web-remote", "apps", 5)}},
+			}
+			provider, err := NewMultiProvider(MultiProviderConfig{ConflictPolicy: tt.policy}, providers[0], providers[1])
+			if err != nil {
+				t.Fatalf("NewMultiProvider() error = %v", err)
+			}
+			if _, err := provider.Load(true); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			// The failed provider's last targets still take part in resolving the conflict
+			providers[tt.failing].err = fmt.Errorf("connection refused")
+			resources, err := provider.Load(true)
+			var multiErr *MultiError
+			if !errors.As(err, &multiErr) || !multiErr.Partial() {
+				t.Fatalf("Load() error = %v, want a partial *MultiError", err)
+			}
+			var names []string
+			for _, res := range resources {
+				names = append(names, res.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Errorf("Load() = %v, want %v", names, tt.wantNames)
+			}
+			conflicts := provider.Conflicts()
+			if len(conflicts) != 1 || conflicts[0].String() != tt.wantMessage {
+				t.Errorf("Conflicts() = %v, want [%s]", conflicts, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import "sync"
+
+// notifier implements WatchProvider for the providers embedding it, fanning their
+// change notifications out to every subscriber
+type notifier struct {
+	subscribers map[<-chan struct{}]chan struct{}
+	mu          sync.Mutex
+}
+
+// Subscribe implements WatchProvider.Subscribe
+func (n *notifier) Subscribe() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.subscribers == nil {
+		n.subscribers = make(map[<-chan struct{}]chan struct{})
+	}
+	// One buffered slot holds a pending change, so notify never blocks
+	changes := make(chan struct{}, 1)
+	n.subscribers[changes] = changes
+	return changes
+}
+
+// Unsubscribe implements WatchProvider.Unsubscribe
+func (n *notifier) Unsubscribe(changes <-chan struct{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if ch, ok := n.subscribers[changes]; ok {
+		delete(n.subscribers, changes)
+		close(ch)
+	}
+}
+
+// notify tells every subscriber the configuration changed
+func (n *notifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, ch := range n.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// A change is already pending
+		}
+	}
+}
+
+// close closes every subscriber's channel, once the provider stops
+func (n *notifier) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for changes, ch := range n.subscribers {
+		delete(n.subscribers, changes)
+		close(ch)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	var n notifier
+	first := n.Subscribe()
+	second := n.Subscribe()
+
+	// Changes made before a subscriber receives are coalesced into one
+	n.notify()
+	n.notify()
+	for i, changes := range []<-chan struct{}{first, second} {
+		select {
+		case <-changes:
+		default:
+			t.Fatalf("subscriber %d was not notified", i)
+		}
+		select {
+		case <-changes:
+			t.Errorf("subscriber %d was notified twice", i)
+		default:
+		}
+	}
+
+	n.Unsubscribe(first)
+	if _, ok := <-first; ok {
+		t.Errorf("Unsubscribe() left the channel open")
+	}
+	// Unsubscribing twice is harmless
+	n.Unsubscribe(first)
+
+	n.notify()
+	if _, ok := <-second; !ok {
+		t.Errorf("remaining subscriber was not notified")
+	}
+
+	n.close()
+	if _, ok := <-second; ok {
+		t.Errorf("close() left the channel open")
+	}
+}
+
+// waitForChange fails the test unless changes is notified within 5 seconds
+func waitForChange(t *testing.T, changes <-chan struct{}) {
+	t.Helper()
+	select {
+	case _, ok := <-changes:
+		if !ok {
+			t.Fatal("changes channel closed, want a notification")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change was notified")
+	}
+}
@@ -0,0 +1,352 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
+
+// ObjectStoreConfig holds the configuration for the object store provider
+type ObjectStoreConfig struct {
+	// Endpoint is the base URL of the S3-compatible service, such as
+	// https://s3.eu-west-1.amazonaws.com or http://minio:9000. Buckets are addressed
+	// path-style.
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	// Prefix selects the objects to load. Only keys ending in .yaml, .yml or .json are read.
+	Prefix string `json:"prefix" yaml:"prefix"`
+	// Region is the region requests are signed for, us-east-1 if empty
+	Region string `json:"region" yaml:"region"`
+	// AccessKeyID and SecretAccessKey sign requests with AWS Signature Version 4.
+	// Requests are sent anonymously when they are empty.
+	AccessKeyID     string `json:"accessKeyId" yaml:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey" yaml:"secretAccessKey"`
+	// SessionToken accompanies temporary credentials
+	SessionToken string        `json:"sessionToken" yaml:"sessionToken"`
+	PollInterval time.Duration `json:"pollInterval" yaml:"pollInterval"`
+	// Timeout limits each request, 10 seconds if zero
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	// TLS configures trusted CAs and a client certificate, nil for the defaults
+	TLS *RemoteTLSConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// ObjectStoreProvider loads resources from the objects under a prefix of an
+// S3-compatible bucket. Each poll lists the prefix and downloads only the objects
+// whose ETag changed. The last content that validated is kept, so a bad upload does
+// not drop the running configuration.
+type ObjectStoreProvider struct {
+	config     ObjectStoreConfig
+	httpClient *http.Client
+	// objects are the parsed objects by key, as of the last listing. Guarded by syncMu.
+	objects   map[string]*storedObject
+	current   *objectStoreRevision
+	lastErr   error
+	fetchedAt time.Time
+	mu        sync.RWMutex
+	// syncMu serializes fetches, so Revision does not wait on one
+	syncMu sync.Mutex
+}
+
+// storedObject is a downloaded and parsed object
+type storedObject struct {
+	etag      string
+	hash      string
+	resources []model.Resource
+}
+
+// objectStoreRevision is a validated parse of the objects
+type objectStoreRevision struct {
+	resources  []model.Resource
+	generation int64
+	hash       string
+}
+
+// listBucketResult is the response to a ListObjectsV2 request
+type listBucketResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		ETag string `xml:"ETag"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// objectStoreError is the error document returned by a failed request
+type objectStoreError struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func NewObjectStoreProvider(config ObjectStoreConfig) (*ObjectStoreProvider, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if (config.AccessKeyID == "") != (config.SecretAccessKey == "") {
+		return nil, fmt.Errorf("access key ID and secret access key must be set together")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	httpClient, err := newHTTPClient(RemoteConfig{Timeout: config.Timeout, TLS: config.TLS})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectStoreProvider{
+		config:     config,
+		httpClient: httpClient,
+		objects:    make(map[string]*storedObject),
+	}, nil
+}
+
+// String describes the provider's source
+func (o *ObjectStoreProvider) String() string {
+	return "s3 " + o.config.Bucket + "/" + o.config.Prefix
+}
+
+// Revision implements RevisionProvider.Revision
+func (o *ObjectStoreProvider) Revision() Revision {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	rev := Revision{Error: o.lastErr}
+	if o.current != nil {
+		rev.Generation = o.current.generation
+		rev.Hash = o.current.hash
+	}
+	return rev
+}
+
+// Load implements Provider.Load
+func (o *ObjectStoreProvider) Load(validate bool) ([]model.Resource, error) {
+	o.syncMu.Lock()
+	defer o.syncMu.Unlock()
+
+	o.mu.RLock()
+	current, fetchedAt := o.current, o.fetchedAt
+	o.mu.RUnlock()
+
+	if validate && current != nil && time.Since(fetchedAt) < o.config.PollInterval {
+		return current.resources, nil
+	}
+
+	resources, hash, err := o.fetch(validate)
+	if err != nil {
+		// Keep serving the last good objects until the bucket is fixed
+		if validate && current != nil {
+			o.mu.Lock()
+			o.lastErr = err
+			o.fetchedAt = time.Now()
+			o.mu.Unlock()
+			return current.resources, nil
+		}
+		return nil, err
+	}
+
+	if validate {
+		o.mu.Lock()
+		o.lastErr = nil
+		o.fetchedAt = time.Now()
+		o.swap(resources, hash)
+		o.mu.Unlock()
+	}
+
+	return resources, nil
+}
+
+// swap makes resources the current revision unless the content is unchanged. Callers hold mu.
+func (o *ObjectStoreProvider) swap(resources []model.Resource, hash string) {
+	if o.current != nil && o.current.hash == hash {
+		return
+	}
+	var generation int64 = 1
+	if o.current != nil {
+		generation = o.current.generation + 1
+	}
+	o.current = &objectStoreRevision{
+		resources:  resources,
+		generation: generation,
+		hash:       hash,
+	}
+}
+
+// fetch lists the objects under the prefix, downloads those that changed and returns
+// the resources of all of them with their content hash
+func (o *ObjectStoreProvider) fetch(validate bool) ([]model.Resource, string, error) {
+	listed, err := o.list()
+	if err != nil {
+		return nil, "", err
+	}
+
+	hash := sha256.New()
+	allResources := []model.Resource{}
+	seen := make(map[string]bool, len(listed))
+	for _, object := range listed {
+		key := object.key
+		seen[key] = true
+		// An unchanged ETag means the object is unchanged, so its last parse is reused
+		stored := o.objects[key]
+		if stored == nil || object.etag == "" || stored.etag != object.etag {
+			if stored, err = o.get(key); err != nil {
+				return nil, "", fmt.Errorf("%s: %w", key, err)
+			}
+			if stored.etag == "" {
+				stored.etag = object.etag
+			}
+			o.objects[key] = stored
+		}
+		hash.Write([]byte(stored.hash))
+
+		if validate {
+			for i := range stored.resources {
+				if err := stored.resources[i].Validate(); err != nil {
+					return nil, "", fmt.Errorf("%s: resource[%d] validation failed: %w", key, i, err)
+				}
+			}
+		}
+		allResources = append(allResources, stored.resources...)
+	}
+
+	for key := range o.objects {
+		if !seen[key] {
+			delete(o.objects, key)
+		}
+	}
+
+	if validate && len(allResources) == 0 {
+		return nil, "", fmt.Errorf("no resources defined under %s/%s", o.config.Bucket, o.config.Prefix)
+	}
+
+	return allResources, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// listedObject is a configuration object found by listing the prefix
+type listedObject struct {
+	key  string
+	etag string
+}
+
+// list lists the configuration objects under the prefix in key order, following
+// continuation tokens
+func (o *ObjectStoreProvider) list() ([]listedObject, error) {
+	var listed []listedObject
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {o.config.Prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		body, _, err := o.request("", query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse object listing: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			if _, err := formatFromPath(object.Key); err != nil {
+				continue
+			}
+			listed = append(listed, listedObject{key: object.Key, etag: object.ETag})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return listed, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// get downloads and parses an object
+func (o *ObjectStoreProvider) get(key string) (*storedObject, error) {
+	body, header, err := o.request(key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch object: %w", err)
+	}
+
+	format, err := formatFromPath(key)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := decodeConfig(body, format)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	provenance := &model.Provenance{
+		Provider: model.ProviderObjectStore,
+		Source:   "s3://" + o.config.Bucket + "/" + key,
+		Revision: header.Get("ETag"),
+		LoadedAt: time.Now(),
+	}
+	for i := range resources {
+		resources[i].Provenance = provenance
+	}
+	return &storedObject{
+		etag:      header.Get("ETag"),
+		hash:      hex.EncodeToString(sum[:]),
+		resources: resources,
+	}, nil
+}
+
+// request sends a signed GET for key in the bucket, or for the bucket itself if key
+// is empty, and returns the response body and headers
+func (o *ObjectStoreProvider) request(key string, query url.Values) ([]byte, http.Header, error) {
+	target := strings.TrimRight(o.config.Endpoint, "/") + "/" + uriEncode(o.config.Bucket, true)
+	if key != "" {
+		target += "/" + uriEncode(key, false)
+	}
+	if len(query) > 0 {
+		target += "?" + canonicalQuery(query)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if o.config.AccessKeyID != "" {
+		signV4(req, sigV4Credentials{
+			accessKeyID:     o.config.AccessKeyID,
+			secretAccessKey: o.config.SecretAccessKey,
+			sessionToken:    o.config.SessionToken,
+		}, o.config.Region, "s3", time.Now())
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var storeErr objectStoreError
+		if xml.Unmarshal(body, &storeErr) == nil && storeErr.Code != "" {
+			return nil, nil, fmt.Errorf("unexpected status code %d: %s: %s", resp.StatusCode, storeErr.Code, storeErr.Message)
+		}
+		return nil, nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, resp.Header, nil
+}
@@ -1,9 +1,87 @@
 package config
 
-import "github.com/berkayuckac/k8schedul8r/pkg/model"
+import (
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+)
 
 // Provider defines the interface for configuration providers
 type Provider interface {
 	// If validate is true, the configuration will be validated before being returned
 	Load(validate bool) ([]model.Resource, error)
 }
+
+// Revision identifies the configuration a provider currently serves
+type Revision struct {
+	// Generation increases each time new configuration takes effect, 0 before the first load
+	Generation int64
+	// Hash is the SHA-256 of the configuration's content
+	Hash string
+	// Commit is the Git commit the configuration was read from, empty for other sources
+	Commit string
+	// Error is the latest failure to load newer configuration, nil once newer content loads
+	Error error
+}
+
+// RevisionProvider is implemented by providers that track which revision of their
+// configuration is in effect
+type RevisionProvider interface {
+	Revision() Revision
+}
+
+// Health reports how the source behind a provider has been responding
+type Health struct {
+	// LastSuccess is when configuration was last fetched, zero if it never was
+	LastSuccess time.Time
+	// LastError is the latest failure, nil once a fetch succeeds
+	LastError error
+	// ConsecutiveFailures counts the fetches that failed since the last success
+	ConsecutiveFailures int
+	// CircuitOpenUntil is when fetching resumes after the circuit breaker opened,
+	// zero while it is closed
+	CircuitOpenUntil time.Time
+}
+
+// HealthProvider is implemented by providers that fetch from a source that can fail
+type HealthProvider interface {
+	Health() Health
+}
+
+// SourceHealth is the health of one of the sources combined by a provider
+type SourceHealth struct {
+	// Source names the source
+	Source string
+	Health
+}
+
+// SourceHealthProvider is implemented by providers that combine several sources and
+// track the health of each
+type SourceHealthProvider interface {
+	SourceHealth() []SourceHealth
+}
+
+// ConflictProvider is implemented by providers that combine sources whose resources
+// can target the same workload
+type ConflictProvider interface {
+	// Conflicts describes the conflicts resolved by the last Load
+	Conflicts() []Conflict
+}
+
+// WatchProvider is implemented by providers that learn of configuration changes as
+// they happen, so their configuration can be reloaded without waiting for the next poll
+type WatchProvider interface {
+	// Subscribe returns a channel that receives a value whenever the configuration
+	// changes. Changes arriving before the last one was received are coalesced, and
+	// the channel is closed when the provider stops.
+	Subscribe() <-chan struct{}
+	// Unsubscribe stops notifications on a channel returned by Subscribe and closes it
+	Unsubscribe(changes <-chan struct{})
+}
+
+// StopProvider is implemented by providers that run background work, such as polling
+// or watching their source, until they are stopped
+type StopProvider interface {
+	// Stop ends the background work, closing the channels returned by Subscribe
+	Stop()
+}
@@ -1,7 +1,8 @@
 package config
 
 import (
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,7 +11,6 @@ import (
 	"time"
 
 	"github.com/berkayuckac/k8schedul8r/pkg/model"
-	"gopkg.in/yaml.v3"
 )
 
 // RemoteConfig holds the configuration for the remote provider
@@ -18,16 +18,40 @@ type RemoteConfig struct {
 	URL          string        `json:"url" yaml:"url"`
 	PollInterval time.Duration `json:"pollInterval" yaml:"pollInterval"`
 	BearerToken  string        `json:"bearerToken" yaml:"bearerToken"`
+	// Auth adds credentials to each request, taking precedence over BearerToken
+	Auth RemoteAuth `json:"-" yaml:"-"`
+	// TLS configures trusted CAs and a client certificate, nil for the defaults
+	TLS *RemoteTLSConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// Verifier, if set, rejects payloads without a valid signature in SignatureHeader
+	Verifier *SignatureVerifier `json:"-" yaml:"-"`
+	// Timeout limits each request, 10 seconds if zero
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	// Retry controls retries and the circuit breaker
+	Retry RetryConfig `json:"retry" yaml:"retry"`
+	// MaxStaleness is how long the cached configuration is served while the endpoint
+	// fails before StalePolicy applies, 0 to serve it indefinitely
+	MaxStaleness time.Duration `json:"maxStaleness" yaml:"maxStaleness"`
+	// StalePolicy is what happens to resources past MaxStaleness: "keep" (default),
+	// "freeze" or "revert"
+	StalePolicy string `json:"stalePolicy" yaml:"stalePolicy"`
 }
 
 // cachedConfig holds a configuration with its metadata
 type cachedConfig struct {
 	resources []model.Resource
 	fetchedAt time.Time
+	// etag and lastModified are the response's validators, sent back on the next
+	// fetch so an unchanged document is answered with 304 Not Modified
+	etag         string
+	lastModified string
+	// revision identifies the content, as recorded in the resources' provenance
+	revision string
 }
 
-// RemoteProvider implements Provider interface for remote HTTP configurations
+// RemoteProvider implements Provider interface for remote HTTP configurations.
+// Subscribers are notified when the background poll fetches changed content.
 type RemoteProvider struct {
+	notifier
 	config     RemoteConfig
 	httpClient *http.Client
 	cache      *cachedConfig
@@ -35,6 +59,8 @@ type RemoteProvider struct {
 	stopCh     chan struct{}
 	stopped    bool
 	stoppedMu  sync.RWMutex
+	health     Health
+	healthMu   sync.RWMutex
 	wg         sync.WaitGroup
 }
 
@@ -47,20 +73,37 @@ func NewRemoteProvider(config RemoteConfig) (*RemoteProvider, error) {
 		return nil, fmt.Errorf("poll interval must be positive")
 	}
 
+	if err := model.ValidateStalePolicy(config.StalePolicy); err != nil {
+		return nil, err
+	}
+
+	if err := validateAuth(config.Auth); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
 	provider := &RemoteProvider{
-		config: config,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second, // reasonable default timeout, TODO configurable?
-		},
-		stopCh: make(chan struct{}),
+		config:     config,
+		httpClient: httpClient,
+		stopCh:     make(chan struct{}),
 	}
 
-	// Start background polling
+	// Start background polling, counted before the goroutine starts so Stop waits for it
+	provider.wg.Add(1)
 	go provider.pollConfig()
 
 	return provider, nil
 }
 
+// String describes the provider's source
+func (r *RemoteProvider) String() string {
+	return "remote " + r.config.URL
+}
+
 // Stop stops the background polling and waits for it to complete
 func (r *RemoteProvider) Stop() {
 	r.stoppedMu.Lock()
@@ -70,12 +113,12 @@ func (r *RemoteProvider) Stop() {
 		close(r.stopCh)
 		r.stopped = true
 		r.wg.Wait()
+		r.notifier.close()
 	}
 }
 
 // pollConfig continuously polls the remote endpoint for configuration updates
 func (r *RemoteProvider) pollConfig() {
-	r.wg.Add(1)
 	defer r.wg.Done()
 
 	ticker := time.NewTicker(r.config.PollInterval)
@@ -86,117 +129,279 @@ func (r *RemoteProvider) pollConfig() {
 		case <-r.stopCh:
 			return
 		case <-ticker.C:
-			if resources, err := r.fetchConfig(true); err == nil {
-				r.updateCache(resources)
+			r.cacheMu.RLock()
+			cache := r.cache
+			r.cacheMu.RUnlock()
+
+			if fetched, err := r.fetchWithRetry(true, cache, r.config.Retry.MaxRetries); err == nil {
+				r.updateCache(fetched)
+				if cache == nil || fetched.revision != cache.revision {
+					r.notify()
+				}
 			}
 		}
 	}
 }
 
 // updateCache updates the cached configuration
-func (r *RemoteProvider) updateCache(resources []model.Resource) {
+func (r *RemoteProvider) updateCache(cache *cachedConfig) {
 	r.cacheMu.Lock()
 	defer r.cacheMu.Unlock()
 
-	r.cache = &cachedConfig{
-		resources: resources,
-		fetchedAt: time.Now(),
+	r.cache = cache
+}
+
+// fetchWithRetry fetches the configuration, retrying transient failures up to retries
+// times with backoff, and records the outcome in the provider's health. While the
+// circuit breaker is open it fails without contacting the endpoint.
+func (r *RemoteProvider) fetchWithRetry(validate bool, cached *cachedConfig, retries int) (*cachedConfig, error) {
+	r.healthMu.RLock()
+	openUntil, failures := r.health.CircuitOpenUntil, r.health.ConsecutiveFailures
+	r.healthMu.RUnlock()
+	if time.Now().Before(openUntil) {
+		return nil, fmt.Errorf("circuit breaker open until %s after %d consecutive failures",
+			openUntil.Format(time.RFC3339), failures)
+	}
+
+	var fetched *cachedConfig
+	err := withRetry(r.config.Retry, retries, r.stopCh, func() (err error) {
+		fetched, err = r.fetchConfig(validate, cached)
+		return err
+	})
+	r.recordFetch(err)
+	if err != nil {
+		return nil, err
+	}
+	return fetched, nil
+}
+
+// recordFetch updates the provider's health with the outcome of a fetch
+func (r *RemoteProvider) recordFetch(err error) {
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+
+	if err == nil {
+		r.health = Health{LastSuccess: time.Now()}
+		return
+	}
+
+	r.health.LastError = err
+	r.health.ConsecutiveFailures++
+	if threshold := r.config.Retry.FailureThreshold; threshold > 0 && r.health.ConsecutiveFailures >= threshold {
+		r.health.CircuitOpenUntil = time.Now().Add(r.config.Retry.OpenDuration)
 	}
 }
 
-// fetchConfig fetches the configuration from the remote endpoint
-func (r *RemoteProvider) fetchConfig(validate bool) ([]model.Resource, error) {
-	req, err := http.NewRequest(http.MethodGet, r.config.URL, nil)
+// Health implements HealthProvider.Health
+func (r *RemoteProvider) Health() Health {
+	r.healthMu.RLock()
+	defer r.healthMu.RUnlock()
+
+	return r.health
+}
+
+// remoteDocument is a response body fetched from a remote endpoint
+type remoteDocument struct {
+	body   []byte
+	header http.Header
+	// notModified is set when the endpoint answered 304 Not Modified, leaving body empty
+	notModified bool
+}
+
+// fetchDocument fetches config.URL with the configured credentials and verifies the
+// body's signature when a Verifier is set. The request is conditional on etag and
+// lastModified when they are set. Failures that may succeed when retried, such as
+// network errors and 5xx responses, are marked retryable.
+func fetchDocument(httpClient *http.Client, config RemoteConfig, etag, lastModified string) (*remoteDocument, error) {
+	req, err := http.NewRequest(http.MethodGet, config.URL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add bearer token if configured
-	if r.config.BearerToken != "" {
-		req.Header.Set("Authorization", "Bearer "+r.config.BearerToken)
+	if err := config.authorize(req); err != nil {
+		return nil, err
 	}
 
 	// Set Accept header based on URL extension
-	if strings.HasSuffix(r.config.URL, ".yaml") || strings.HasSuffix(r.config.URL, ".yml") {
+	if strings.HasSuffix(config.URL, ".yaml") || strings.HasSuffix(config.URL, ".yml") {
 		req.Header.Set("Accept", "application/yaml")
 	} else {
 		req.Header.Set("Accept", "application/json")
 	}
 
-	resp, err := r.httpClient.Do(req)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch configuration: %w", err)
+		return nil, &retryableError{fmt.Errorf("failed to fetch %s: %w", config.URL, err)}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return &remoteDocument{header: resp.Header, notModified: true}, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		err := fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &retryableError{err}
+		}
+		return nil, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, &retryableError{fmt.Errorf("failed to read response body: %w", err)}
 	}
 
-	var resources []model.Resource
+	if config.Verifier != nil {
+		if err := config.Verifier.Verify(body, resp.Header.Get(SignatureHeader)); err != nil {
+			return nil, fmt.Errorf("signature verification failed: %w", err)
+		}
+	}
+
+	return &remoteDocument{body: body, header: resp.Header}, nil
+}
+
+// fetchConfig fetches the configuration from the remote endpoint. The request is
+// conditional on cached's validators; when the endpoint answers 304 Not Modified,
+// cached's resources are returned with a fresh timestamp.
+func (r *RemoteProvider) fetchConfig(validate bool, cached *cachedConfig) (*cachedConfig, error) {
+	var etag, lastModified string
+	if cached != nil {
+		etag, lastModified = cached.etag, cached.lastModified
+	}
+
+	doc, err := fetchDocument(r.httpClient, r.config, etag, lastModified)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.notModified {
+		if cached == nil {
+			return nil, fmt.Errorf("unexpected status code %d without a cached configuration", http.StatusNotModified)
+		}
+		// Validate a copy, since Load may be handing the cached resources out meanwhile
+		resources := append([]model.Resource(nil), cached.resources...)
+		if validate {
+			if err := validateResources(resources); err != nil {
+				return nil, err
+			}
+		}
+		return &cachedConfig{
+			resources:    resources,
+			fetchedAt:    time.Now(),
+			etag:         cached.etag,
+			lastModified: cached.lastModified,
+			revision:     cached.revision,
+		}, nil
+	}
 
 	// Try to determine the content type from the response
-	contentType := resp.Header.Get("Content-Type")
+	format := FormatJSON
+	contentType := doc.header.Get("Content-Type")
 	if strings.Contains(contentType, "yaml") || strings.Contains(contentType, "yml") ||
 		strings.HasSuffix(r.config.URL, ".yaml") || strings.HasSuffix(r.config.URL, ".yml") {
-		if err := yaml.Unmarshal(body, &resources); err != nil {
-			return nil, fmt.Errorf("failed to parse YAML configuration: %w", err)
-		}
-	} else {
-		if err := json.Unmarshal(body, &resources); err != nil {
-			return nil, fmt.Errorf("failed to parse JSON configuration: %w", err)
-		}
+		format = FormatYAML
+	}
+
+	resources, err := decodeConfig(doc.body, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Identify the content by its ETag, or its modification time or hash without one
+	revision := doc.header.Get("ETag")
+	if revision == "" {
+		revision = doc.header.Get("Last-Modified")
+	}
+	if revision == "" {
+		sum := sha256.Sum256(doc.body)
+		revision = hex.EncodeToString(sum[:])
+	}
+	provenance := &model.Provenance{
+		Provider: model.ProviderRemote,
+		Source:   r.config.URL,
+		Revision: revision,
+		LoadedAt: time.Now(),
+	}
+	for i := range resources {
+		resources[i].Provenance = provenance
 	}
 
 	if validate {
-		for i, res := range resources {
-			if err := res.Validate(); err != nil {
-				return nil, fmt.Errorf("resource[%d] validation failed: %w", i, err)
-			}
+		if err := validateResources(resources); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cachedConfig{
+		resources:    resources,
+		fetchedAt:    time.Now(),
+		etag:         doc.header.Get("ETag"),
+		lastModified: doc.header.Get("Last-Modified"),
+		revision:     revision,
+	}, nil
+}
+
+// validateResources validates each resource in turn
+func validateResources(resources []model.Resource) error {
+	for i := range resources {
+		if err := resources[i].Validate(); err != nil {
+			return fmt.Errorf("resource[%d] validation failed: %w", i, err)
 		}
 	}
+	return nil
+}
+
+// staleResources returns the cached resources, marked stale once the cache is older
+// than MaxStaleness
+func (r *RemoteProvider) staleResources(cache *cachedConfig) []model.Resource {
+	if r.config.MaxStaleness <= 0 || time.Since(cache.fetchedAt) <= r.config.MaxStaleness {
+		return cache.resources
+	}
 
-	return resources, nil
+	policy := r.config.StalePolicy
+	if policy == "" {
+		policy = model.StalePolicyKeep
+	}
+	resources := make([]model.Resource, len(cache.resources))
+	for i, res := range cache.resources {
+		res.Stale = &model.Staleness{Policy: policy, FetchedAt: cache.fetchedAt}
+		resources[i] = res
+	}
+	return resources
 }
 
-// Load implements Provider.Load
+// Load implements Provider.Load. The background poll keeps the cache fresh, retrying
+// failed fetches, so Load serves the cache and only fetches itself, once and without
+// retrying, before anything has been cached.
 func (r *RemoteProvider) Load(validate bool) ([]model.Resource, error) {
 	r.cacheMu.RLock()
 	cache := r.cache
 	r.cacheMu.RUnlock()
 
-	// If we have a valid cache, return it
-	if cache != nil && time.Since(cache.fetchedAt) < r.config.PollInterval {
-		return cache.resources, nil
-	}
-
-	r.cacheMu.Lock()
-	defer r.cacheMu.Unlock()
-
-	// Double-check cache under write lock
-	if r.cache != nil && time.Since(r.cache.fetchedAt) < r.config.PollInterval {
-		return r.cache.resources, nil
+	if cache != nil {
+		return r.staleResources(cache), nil
 	}
 
-	// Try to fetch new config
-	resources, err := r.fetchConfig(validate)
+	fetched, err := r.fetchWithRetry(validate, nil, 0)
 	if err != nil {
-		// On error, try to return cached config if available
-		if r.cache != nil {
-			return r.cache.resources, nil
-		}
 		return nil, err
 	}
 
-	r.cache = &cachedConfig{
-		resources: resources,
-		fetchedAt: time.Now(),
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	// Keep whatever the background poll cached while the fetch was in flight
+	if r.cache != nil {
+		return r.staleResources(r.cache), nil
 	}
-	return resources, nil
+	r.cache = fetched
+	return fetched.resources, nil
 }
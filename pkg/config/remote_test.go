@@ -5,8 +5,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
 )
 
 func TestNewRemoteProvider(t *testing.T) {
@@ -387,3 +390,366 @@ func TestRemoteProvider_BackgroundPolling(t *testing.T) {
 		t.Errorf("expected 3-5 requests, got %d", requestCount)
 	}
 }
+
+func TestRemoteProvider_Load_NotModified(t *testing.T) {
+	validConfig := `[{"name": "test-scaler", "namespace": "default", "target": {"name": "test-deployment", "kind": "Deployment"}, "originalReplicas": 2, "windows": [{"days": ["Mon-Fri"], "startTimeOfDay": "08:00", "endTimeOfDay": "18:00", "replicas": 3}]}]`
+	lastModified := time.Now().UTC().Format(http.TimeFormat)
+
+	var mu sync.Mutex
+	var fullResponses, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == lastModified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", lastModified)
+		fmt.Fprint(w, validConfig)
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Stop()
+
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			// Let the background poll revalidate the cache
+			time.Sleep(60 * time.Millisecond)
+		}
+		resources, err := provider.Load(true)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(resources) != 1 || resources[0].Name != "test-scaler" {
+			t.Fatalf("Load() = %+v, want the cached test-scaler resource", resources)
+		}
+		if p := resources[0].Provenance; p == nil || p.Source != server.URL || p.Revision != `"v1"` {
+			t.Errorf("Load() provenance = %+v, want %s at ETag \"v1\"", p, server.URL)
+		}
+	}
+
+	mu.Lock()
+	if fullResponses != 1 || notModified < 2 {
+		t.Errorf("got %d full responses and %d not modified, want 1 and at least 2", fullResponses, notModified)
+	}
+	mu.Unlock()
+
+	provider.cacheMu.RLock()
+	defer provider.cacheMu.RUnlock()
+	if provider.cache.etag != `"v1"` || time.Since(provider.cache.fetchedAt) > 100*time.Millisecond {
+		t.Errorf("cache = %+v, want the ETag kept and a refreshed timestamp", provider.cache)
+	}
+}
+
+func TestRemoteProvider_FetchWithRetry(t *testing.T) {
+	validConfig := `[{"name": "test-scaler", "namespace": "default", "target": {"name": "test-deployment", "kind": "Deployment"}, "originalReplicas": 2, "windows": []}]`
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxRetries   int
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "retries server errors until success",
+			statuses:     []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK},
+			maxRetries:   3,
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after max retries",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			maxRetries:   2,
+			wantRequests: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusUnauthorized, http.StatusOK},
+			maxRetries:   3,
+			wantRequests: 1,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[requests]
+				requests++
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					fmt.Fprint(w, validConfig)
+				}
+			}))
+			defer server.Close()
+
+			provider, err := NewRemoteProvider(RemoteConfig{
+				URL:          server.URL,
+				PollInterval: time.Minute,
+				Retry: RetryConfig{
+					MaxRetries:     tt.maxRetries,
+					InitialBackoff: time.Millisecond,
+					MaxBackoff:     5 * time.Millisecond,
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create provider: %v", err)
+			}
+			defer provider.Stop()
+
+			_, err = provider.fetchWithRetry(true, nil, tt.maxRetries)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fetchWithRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", requests, tt.wantRequests)
+			}
+
+			health := provider.Health()
+			if tt.wantErr {
+				if health.ConsecutiveFailures != 1 || health.LastError == nil || !health.LastSuccess.IsZero() {
+					t.Errorf("Health() = %+v, want one failure and no success", health)
+				}
+			} else if health.ConsecutiveFailures != 0 || health.LastError != nil || health.LastSuccess.IsZero() {
+				t.Errorf("Health() = %+v, want a success", health)
+			}
+		})
+	}
+}
+
+func TestRemoteProvider_Load_NoRetry(t *testing.T) {
+	var mu sync.Mutex
+	failing := true
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		fmt.Fprint(w, localConfig(3))
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: time.Minute,
+		Retry:        RetryConfig{MaxRetries: 3, InitialBackoff: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Stop()
+
+	// Without a cache Load fetches once, leaving retries to the background poll
+	start := time.Now()
+	if _, err := provider.Load(true); err == nil {
+		t.Fatal("Load() succeeded against a failing endpoint")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Load() took %v, want no backoff", elapsed)
+	}
+
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	if _, err := provider.Load(true); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Once cached, Load serves the cache even past the poll interval without a request
+	provider.cacheMu.Lock()
+	provider.cache.fetchedAt = time.Now().Add(-time.Hour)
+	provider.cacheMu.Unlock()
+	mu.Lock()
+	failing = true
+	mu.Unlock()
+	resources, err := provider.Load(true)
+	if err != nil || len(resources) != 1 {
+		t.Errorf("Load() = %+v, %v, want the cached resource", resources, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+}
+
+func TestRemoteProvider_CircuitBreaker(t *testing.T) {
+	failing := true
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `[{"name": "test-scaler", "namespace": "default", "target": {"name": "test-deployment", "kind": "Deployment"}, "originalReplicas": 2, "windows": []}]`)
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: time.Minute,
+		Retry: RetryConfig{
+			FailureThreshold: 2,
+			OpenDuration:     50 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Stop()
+
+	// Two failed fetches open the breaker
+	for i := 0; i < 2; i++ {
+		if _, err := provider.Load(true); err == nil {
+			t.Fatal("Load() succeeded against a failing endpoint")
+		}
+	}
+	health := provider.Health()
+	if health.ConsecutiveFailures != 2 || !time.Now().Before(health.CircuitOpenUntil) {
+		t.Fatalf("Health() = %+v, want an open circuit breaker after 2 failures", health)
+	}
+
+	// While open, the endpoint is left alone
+	if _, err := provider.Load(true); err == nil || !strings.Contains(err.Error(), "circuit breaker open") {
+		t.Errorf("Load() with open breaker error = %v, want circuit breaker open", err)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+
+	// Once the open duration passes, a successful fetch closes it
+	failing = false
+	time.Sleep(60 * time.Millisecond)
+	if _, err := provider.Load(true); err != nil {
+		t.Fatalf("Load() after breaker reopened error = %v", err)
+	}
+	if health := provider.Health(); health.ConsecutiveFailures != 0 || !health.CircuitOpenUntil.IsZero() {
+		t.Errorf("Health() = %+v, want a closed breaker", health)
+	}
+}
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	retry := RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for i, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		for j := 0; j < 20; j++ {
+			if wait := retry.backoff(i); wait < max/2 || wait > max {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", i, wait, max/2, max)
+			}
+		}
+	}
+}
+
+func TestRemoteProvider_Load_Stale(t *testing.T) {
+	failing := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `[{"name": "test-scaler", "namespace": "default", "target": {"name": "test-deployment", "kind": "Deployment"}, "originalReplicas": 2, "windows": []}]`)
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: 10 * time.Millisecond,
+		MaxStaleness: 100 * time.Millisecond,
+		StalePolicy:  model.StalePolicyRevert,
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	provider.Stop()
+
+	if _, err := provider.Load(true); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	failing = true
+
+	// Within the maximum staleness the cache is served as is
+	time.Sleep(20 * time.Millisecond)
+	resources, err := provider.Load(true)
+	if err != nil || len(resources) != 1 || resources[0].Stale != nil {
+		t.Fatalf("Load() = %+v, %v, want the fresh cached resource", resources, err)
+	}
+
+	// Past it the resources are marked with the policy
+	time.Sleep(100 * time.Millisecond)
+	resources, err = provider.Load(true)
+	if err != nil || len(resources) != 1 {
+		t.Fatalf("Load() = %+v, %v, want the cached resource", resources, err)
+	}
+	if resources[0].Stale == nil || resources[0].Stale.Policy != model.StalePolicyRevert {
+		t.Errorf("Stale = %+v, want the revert policy", resources[0].Stale)
+	}
+
+	if _, err := NewRemoteProvider(RemoteConfig{URL: server.URL, PollInterval: time.Second, StalePolicy: "ignore"}); err == nil {
+		t.Error("NewRemoteProvider() with an unknown stale policy succeeded")
+	}
+}
+
+func TestRemoteProvider_Subscribe(t *testing.T) {
+	var mu sync.Mutex
+	replicas := 3
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/yaml")
+		fmt.Fprint(w, localConfig(replicas))
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteProvider(RemoteConfig{URL: server.URL, PollInterval: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Stop()
+	changes := provider.Subscribe()
+
+	// The first poll fetches the configuration
+	waitForChange(t, changes)
+
+	// Polls returning the same content are not changes
+	time.Sleep(200 * time.Millisecond)
+	select {
+	case <-changes:
+		t.Fatal("unchanged content was notified")
+	default:
+	}
+
+	mu.Lock()
+	replicas = 5
+	mu.Unlock()
+	waitForChange(t, changes)
+
+	resources, err := provider.Load(true)
+	if err != nil || resources[0].Windows[0].Replicas != 5 {
+		t.Errorf("Load() after change = %+v, %v, want 5 replicas", resources, err)
+	}
+
+	provider.Stop()
+	if _, ok := <-changes; ok {
+		t.Errorf("Stop() left the changes channel open")
+	}
+}
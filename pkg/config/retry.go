@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryConfig controls how failed remote fetches are retried and when the endpoint is
+// left alone for a while. The zero value neither retries nor breaks the circuit.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after a failed attempt
+	MaxRetries int `json:"maxRetries" yaml:"maxRetries"`
+	// InitialBackoff is the wait before the first retry. It doubles for every further
+	// retry up to MaxBackoff, and each wait is jittered by up to half its length.
+	InitialBackoff time.Duration `json:"initialBackoff" yaml:"initialBackoff"`
+	MaxBackoff     time.Duration `json:"maxBackoff" yaml:"maxBackoff"`
+	// FailureThreshold is the number of consecutive failed fetches that opens the
+	// circuit breaker, 0 to never open it
+	FailureThreshold int `json:"failureThreshold" yaml:"failureThreshold"`
+	// OpenDuration is how long an open circuit breaker skips fetches. The next fetch
+	// after it closes the breaker on success and opens it again on failure.
+	OpenDuration time.Duration `json:"openDuration" yaml:"openDuration"`
+}
+
+// backoff returns the jittered wait before the given retry, counting from 0
+func (c RetryConfig) backoff(retry int) time.Duration {
+	wait := c.InitialBackoff
+	for i := 0; i < retry && (c.MaxBackoff <= 0 || wait < c.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if c.MaxBackoff > 0 && wait > c.MaxBackoff {
+		wait = c.MaxBackoff
+	}
+	if half := int64(wait / 2); half > 0 {
+		wait = time.Duration(half + rand.Int63n(half+1))
+	}
+	return wait
+}
+
+// retryableError marks a failure that may succeed when retried, such as a network
+// error or a 5xx response
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// isRetryable reports whether err may succeed when retried
+func isRetryable(err error) bool {
+	var retryable *retryableError
+	return errors.As(err, &retryable)
+}
+
+// withRetry calls fetch until it succeeds, fails with an error that is not retryable,
+// or has been retried retries times, waiting config's backoff between attempts. It
+// returns the last error early when stopCh is closed.
+func withRetry(config RetryConfig, retries int, stopCh <-chan struct{}, fetch func() error) error {
+	for retry := 0; ; retry++ {
+		err := fetch()
+		if err == nil || retry >= retries || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(config.backoff(retry)):
+		case <-stopCh:
+			return err
+		}
+	}
+}
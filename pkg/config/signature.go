@@ -0,0 +1,182 @@
+package config
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SignatureHeader is the response header carrying a remote configuration's signature
+const SignatureHeader = "X-Config-Signature"
+
+// SignatureVerifier checks that a payload was signed by one of a set of trusted Ed25519 keys.
+// A signature is either the base64-encoded raw Ed25519 signature of the payload, or a
+// compact JWS with a detached payload (RFC 7515, appendix F) using the EdDSA algorithm.
+type SignatureVerifier struct {
+	keys []trustedKey
+}
+
+// trustedKey is a public key with the ID a JWS can refer to it by
+type trustedKey struct {
+	id  string
+	key ed25519.PublicKey
+}
+
+// jwsHeader is the protected header of a JWS
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwkSet is a JSON Web Key Set holding Ed25519 keys
+type jwkSet struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Kid string `json:"kid"`
+	} `json:"keys"`
+}
+
+// NewSignatureVerifier returns a verifier trusting keys, indexed by key ID
+func NewSignatureVerifier(keys map[string]ed25519.PublicKey) (*SignatureVerifier, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one public key is required")
+	}
+	v := &SignatureVerifier{}
+	for id, key := range keys {
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("public key %q is not an Ed25519 key", id)
+		}
+		v.keys = append(v.keys, trustedKey{id: id, key: key})
+	}
+	return v, nil
+}
+
+// LoadSignatureVerifier reads the trusted keys from a file holding either PEM-encoded
+// public keys or a JSON Web Key Set. PEM keys are identified by their "kid" header if set.
+func LoadSignatureVerifier(path string) (*SignatureVerifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public keys: %w", err)
+	}
+
+	keys := make(map[string]ed25519.PublicKey)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var set jwkSet
+		if err := json.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("failed to parse JWK set: %w", err)
+		}
+		for i, jwk := range set.Keys {
+			if jwk.Kty != "OKP" || jwk.Crv != "Ed25519" {
+				return nil, fmt.Errorf("key[%d]: unsupported key type %s/%s", i, jwk.Kty, jwk.Crv)
+			}
+			x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+			if err != nil {
+				return nil, fmt.Errorf("key[%d]: invalid public key: %w", i, err)
+			}
+			keys[keyID(jwk.Kid, i)] = x
+		}
+	} else {
+		for i := 0; ; i++ {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("key[%d]: invalid public key: %w", i, err)
+			}
+			key, ok := pub.(ed25519.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("key[%d]: not an Ed25519 key", i)
+			}
+			keys[keyID(block.Headers["kid"], i)] = key
+		}
+	}
+
+	return NewSignatureVerifier(keys)
+}
+
+// keyID returns kid, or a positional ID for keys without one
+func keyID(kid string, index int) string {
+	if kid != "" {
+		return kid
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+// Verify checks signature over payload against the trusted keys
+func (v *SignatureVerifier) Verify(payload []byte, signature string) error {
+	signature = strings.TrimSpace(signature)
+	if signature == "" {
+		return fmt.Errorf("configuration is not signed")
+	}
+
+	if strings.Contains(signature, ".") {
+		return v.verifyJWS(payload, signature)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		if sig, err = base64.RawURLEncoding.DecodeString(signature); err != nil {
+			return fmt.Errorf("invalid signature encoding: %w", err)
+		}
+	}
+	for _, k := range v.keys {
+		if ed25519.Verify(k.key, payload, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature does not match any trusted key")
+}
+
+// verifyJWS checks a compact JWS whose payload is detached
+func (v *SignatureVerifier) verifyJWS(payload []byte, jws string) error {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid JWS: expected 3 parts, got %d", len(parts))
+	}
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	if parts[1] != "" && parts[1] != encodedPayload {
+		return fmt.Errorf("invalid JWS: embedded payload does not match the configuration")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("invalid JWS header: %w", err)
+	}
+	var header jwsHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return fmt.Errorf("invalid JWS header: %w", err)
+	}
+	if header.Alg != "EdDSA" {
+		return fmt.Errorf("unsupported JWS algorithm %q", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("invalid JWS signature encoding: %w", err)
+	}
+
+	signingInput := []byte(parts[0] + "." + encodedPayload)
+	for _, k := range v.keys {
+		if header.Kid != "" && k.id != header.Kid {
+			continue
+		}
+		if ed25519.Verify(k.key, signingInput, sig) {
+			return nil
+		}
+	}
+	if header.Kid != "" {
+		return fmt.Errorf("signature does not match trusted key %q", header.Kid)
+	}
+	return fmt.Errorf("signature does not match any trusted key")
+}
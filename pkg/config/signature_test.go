@@ -0,0 +1,197 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signJWS returns a compact JWS over payload with the payload detached
+func signJWS(t *testing.T, key ed25519.PrivateKey, kid string, payload []byte) string {
+	t.Helper()
+	header, err := json.Marshal(jwsHeader{Alg: "EdDSA", Kid: kid})
+	if err != nil {
+		t.Fatalf("failed to marshal JWS header: %v", err)
+	}
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+	signingInput := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signingInput)))
+}
+
+func TestSignatureVerifier_Verify(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	otherPub, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+	verifier, err := NewSignatureVerifier(map[string]ed25519.PublicKey{"main": pub, "other": otherPub})
+	if err != nil {
+		t.Fatalf("NewSignatureVerifier() error = %v", err)
+	}
+
+	payload := []byte(authTestConfig)
+	_, untrusted, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name        string
+		signature   string
+		payload     []byte
+		errContains string
+	}{
+		{
+			name:      "raw signature",
+			signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload)),
+		},
+		{
+			name:      "raw signature from second key",
+			signature: base64.RawURLEncoding.EncodeToString(ed25519.Sign(otherPriv, payload)),
+		},
+		{
+			name:      "detached JWS",
+			signature: signJWS(t, priv, "main", payload),
+		},
+		{
+			name:      "detached JWS without key ID",
+			signature: signJWS(t, otherPriv, "", payload),
+		},
+		{
+			name:        "unsigned",
+			errContains: "not signed",
+		},
+		{
+			name:        "tampered payload",
+			signature:   base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload)),
+			payload:     []byte(strings.Replace(authTestConfig, `"replicas": 3`, `"replicas": 30`, 1)),
+			errContains: "does not match any trusted key",
+		},
+		{
+			name:        "untrusted key",
+			signature:   signJWS(t, untrusted, "", payload),
+			errContains: "does not match any trusted key",
+		},
+		{
+			name:        "JWS naming another key",
+			signature:   signJWS(t, priv, "other", payload),
+			errContains: `does not match trusted key "other"`,
+		},
+		{
+			name:        "unsupported JWS algorithm",
+			signature:   base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "..",
+			errContains: `unsupported JWS algorithm "none"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := payload
+			if tt.payload != nil {
+				data = tt.payload
+			}
+			err := verifier.Verify(data, tt.signature)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Verify() error = %v, want it to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestLoadSignatureVerifier(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	payload := []byte(authTestConfig)
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	pemFile := writeTestFile(t, dir, "keys.pem", pem.EncodeToMemory(&pem.Block{
+		Type:    "PUBLIC KEY",
+		Headers: map[string]string{"kid": "main"},
+		Bytes:   der,
+	}))
+	jwksFile := writeTestFile(t, dir, "keys.json", []byte(fmt.Sprintf(
+		`{"keys": [{"kty": "OKP", "crv": "Ed25519", "kid": "main", "x": %q}]}`,
+		base64.RawURLEncoding.EncodeToString(pub))))
+
+	for _, path := range []string{pemFile, jwksFile} {
+		verifier, err := LoadSignatureVerifier(path)
+		if err != nil {
+			t.Fatalf("LoadSignatureVerifier(%s) error = %v", path, err)
+		}
+		if err := verifier.Verify(payload, signJWS(t, priv, "main", payload)); err != nil {
+			t.Errorf("Verify() with keys from %s error = %v", path, err)
+		}
+	}
+
+	emptyFile := writeTestFile(t, dir, "empty.pem", nil)
+	if _, err := LoadSignatureVerifier(emptyFile); err == nil || !strings.Contains(err.Error(), "at least one public key") {
+		t.Errorf("LoadSignatureVerifier() with no keys error = %v, want at least one public key", err)
+	}
+	rsaFile := writeTestFile(t, dir, "rsa.json", []byte(`{"keys": [{"kty": "RSA"}]}`))
+	if _, err := LoadSignatureVerifier(rsaFile); err == nil || !strings.Contains(err.Error(), "unsupported key type") {
+		t.Errorf("LoadSignatureVerifier() with an RSA key error = %v, want unsupported key type", err)
+	}
+}
+
+func TestRemoteProvider_Load_Signed(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	verifier, err := NewSignatureVerifier(map[string]ed25519.PublicKey{"main": pub})
+	if err != nil {
+		t.Fatalf("NewSignatureVerifier() error = %v", err)
+	}
+
+	tampered := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := []byte(authTestConfig)
+		w.Header().Set(SignatureHeader, base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload)))
+		if tampered {
+			payload = []byte(strings.Replace(authTestConfig, `"replicas": 3`, `"replicas": 30`, 1))
+		}
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	provider, err := NewRemoteProvider(RemoteConfig{
+		URL:          server.URL,
+		PollInterval: 50 * time.Millisecond,
+		Verifier:     verifier,
+	})
+	if err != nil {
+		t.Fatalf("NewRemoteProvider() error = %v", err)
+	}
+	provider.Stop()
+
+	resources, err := provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if resources[0].Windows[0].Replicas != 3 {
+		t.Fatalf("Load() replicas = %d, want 3", resources[0].Windows[0].Replicas)
+	}
+
+	// A tampered payload is rejected and the trusted cache kept
+	tampered = true
+	time.Sleep(60 * time.Millisecond)
+	if _, err := provider.fetchConfig(true, nil); err == nil || !strings.Contains(err.Error(), "signature verification failed") {
+		t.Errorf("fetchConfig() of tampered payload error = %v, want signature verification failed", err)
+	}
+	resources, err = provider.Load(true)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if resources[0].Windows[0].Replicas != 3 {
+		t.Errorf("Load() after tampering replicas = %d, want the trusted 3", resources[0].Windows[0].Replicas)
+	}
+}
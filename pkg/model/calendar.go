@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar is a named list of days, such as public holidays or company shutdowns,
+// on which a resource's scaling windows are suppressed
+type Calendar struct {
+	// Name is how resources reference the calendar
+	Name string `json:"name" yaml:"name"`
+	// Days lists the single days and date ranges in the calendar
+	Days []CalendarDay `json:"days" yaml:"days"`
+}
+
+// CalendarDay is a single date or an inclusive range of dates
+type CalendarDay struct {
+	// Date is the first day of the entry (YYYY-MM-DD)
+	Date string `json:"date" yaml:"date"`
+	// EndDate is the optional last day of a range (YYYY-MM-DD, inclusive)
+	EndDate string `json:"endDate,omitempty" yaml:"endDate,omitempty"`
+	// Description is free-form, e.g. "New Year's Day"
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Contains reports whether the calendar date of t, in t's location, is listed
+func (c *Calendar) Contains(t time.Time) bool {
+	// YYYY-MM-DD strings sort chronologically, so plain comparison is enough
+	date := t.Format(dateLayout)
+	for _, day := range c.Days {
+		end := day.EndDate
+		if end == "" {
+			end = day.Date
+		}
+		if date >= day.Date && date <= end {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Calendar) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("calendar name is required")
+	}
+	for i, day := range c.Days {
+		start, err := time.Parse(dateLayout, day.Date)
+		if err != nil {
+			return fmt.Errorf("calendar %s: day %d has invalid date %q, expected YYYY-MM-DD", c.Name, i, day.Date)
+		}
+		if day.EndDate == "" {
+			continue
+		}
+		end, err := time.Parse(dateLayout, day.EndDate)
+		if err != nil {
+			return fmt.Errorf("calendar %s: day %d has invalid end date %q, expected YYYY-MM-DD", c.Name, i, day.EndDate)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("calendar %s: day %d ends before it starts", c.Name, i)
+		}
+	}
+	return nil
+}
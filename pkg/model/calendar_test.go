@@ -0,0 +1,99 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar_Contains(t *testing.T) {
+	calendar := Calendar{
+		Name: "holidays",
+		Days: []CalendarDay{
+			{Date: "2024-12-25", Description: "Christmas Day"},
+			{Date: "2024-12-30", EndDate: "2025-01-01"},
+		},
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected bool
+	}{
+		{name: "single day", t: time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC), expected: true},
+		{name: "day before single day", t: time.Date(2024, 12, 24, 23, 59, 0, 0, time.UTC), expected: false},
+		{name: "start of range", t: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC), expected: true},
+		{name: "inside range across year", t: time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC), expected: true},
+		{name: "end of range is inclusive", t: time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC), expected: true},
+		{name: "after range", t: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), expected: false},
+		{name: "local date differs from UTC date", t: time.Date(2024, 12, 24, 23, 30, 0, 0, time.UTC).In(berlin), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.Contains(tt.t); got != tt.expected {
+				t.Errorf("Calendar.Contains() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalendar_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		calendar    Calendar
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid calendar",
+			calendar: Calendar{
+				Name: "holidays",
+				Days: []CalendarDay{{Date: "2024-12-25"}, {Date: "2024-12-30", EndDate: "2025-01-01"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "missing name",
+			calendar:    Calendar{Days: []CalendarDay{{Date: "2024-12-25"}}},
+			wantErr:     true,
+			errContains: "calendar name is required",
+		},
+		{
+			name:        "invalid date",
+			calendar:    Calendar{Name: "holidays", Days: []CalendarDay{{Date: "25/12/2024"}}},
+			wantErr:     true,
+			errContains: "day 0 has invalid date",
+		},
+		{
+			name:        "invalid end date",
+			calendar:    Calendar{Name: "holidays", Days: []CalendarDay{{Date: "2024-12-25", EndDate: "soon"}}},
+			wantErr:     true,
+			errContains: "day 0 has invalid end date",
+		},
+		{
+			name:        "range ends before start",
+			calendar:    Calendar{Name: "holidays", Days: []CalendarDay{{Date: "2024-12-25", EndDate: "2024-12-24"}}},
+			wantErr:     true,
+			errContains: "ends before it starts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.calendar.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Calendar.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && err != nil && tt.errContains != "" {
+				if !contains(err.Error(), tt.errContains) {
+					t.Errorf("Calendar.Validate() error = %v, should contain %v", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
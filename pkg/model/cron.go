@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser accepts standard five-field cron expressions and descriptors like "@daily"
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// parseCron parses the window's schedule and duration
+func (w *ScalingWindow) parseCron() (cron.Schedule, time.Duration, error) {
+	schedule, err := cronParser.Parse(w.Schedule)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid cron schedule %q: %w", w.Schedule, err)
+	}
+	if w.Duration == "" {
+		return nil, 0, fmt.Errorf("cron windows require a duration")
+	}
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid duration %q: %w", w.Duration, err)
+	}
+	if duration <= 0 {
+		return nil, 0, fmt.Errorf("duration must be positive")
+	}
+	return schedule, duration, nil
+}
+
+// maxZoneShift bounds how far a DST change moves a zone's UTC offset. Scanning this
+// far either side of an instant's wall-clock time finds every activation covering it.
+const maxZoneShift = 2 * time.Hour
+
+// wallClock returns t's wall-clock reading in its location as a UTC time, so that
+// schedules can be matched against it without DST transitions in the way
+func wallClock(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// activations calls fn with the start of each activation of schedule whose wall-clock
+// time, as returned by wallClock, is after from and not after to, until fn returns
+// false. Activations are resolved on loc's wall clock like recurring windows: one in
+// a spring-forward gap starts at the end of the gap, and one in a repeated fall-back
+// hour starts at its first occurrence only.
+func activations(schedule cron.Schedule, from, to time.Time, loc *time.Location, fn func(start time.Time) bool) {
+	// Next returns the zero time for a schedule that never fires, such as February 30th
+	for next := schedule.Next(from); !next.IsZero() && !next.After(to); next = schedule.Next(next) {
+		year, month, day := next.Date()
+		sec := int64(next.Hour()*3600 + next.Minute()*60 + next.Second())
+		if !fn(wallTime(year, month, day, sec, loc)) {
+			return
+		}
+	}
+}
+
+// isCronActive reports whether an activation of the schedule, matched on the wall
+// clock of t's location, started less than Duration before t
+func (w *ScalingWindow) isCronActive(t time.Time) bool {
+	schedule, duration, err := w.parseCron()
+	if err != nil {
+		return false
+	}
+
+	wall := wallClock(t)
+	active := false
+	activations(schedule, wall.Add(-duration-2*maxZoneShift), wall.Add(maxZoneShift), t.Location(), func(start time.Time) bool {
+		active = !t.Before(start) && t.Before(start.Add(duration))
+		return !active
+	})
+	return active
+}
+
+// validateCron checks the cron fields of a window
+func (w *ScalingWindow) validateCron() error {
+	if w.StartTime != 0 || w.EndTime != 0 {
+		return fmt.Errorf("cron windows cannot also set startTime or endTime")
+	}
+	if len(w.Days) > 0 || w.StartTimeOfDay != "" || w.EndTimeOfDay != "" {
+		return fmt.Errorf("cron windows cannot also set days or times of day")
+	}
+	schedule, _, err := w.parseCron()
+	if err != nil {
+		return err
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("cron schedule %q never fires", w.Schedule)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package model
+
+import "fmt"
+
+// CronJob targets are suspended rather than scaled: a replica count of 0 suspends
+// the CronJob and 1 lets it run on its schedule
+const (
+	// CronJobSuspended is the replica count that suspends a CronJob target
+	CronJobSuspended int32 = 0
+	// CronJobActive is the replica count that resumes a CronJob target
+	CronJobActive int32 = 1
+)
+
+// IsCronJob reports whether the target is a batch CronJob
+func (t *Target) IsCronJob() bool {
+	gvk, err := t.GroupVersionKind()
+	return err == nil && gvk.Group == "batch" && gvk.Kind == "CronJob"
+}
+
+// validateCronJob checks that a CronJob target only uses the suspended and active replica counts
+func (r *Resource) validateCronJob() error {
+	if !r.Target.IsCronJob() {
+		return nil
+	}
+
+	validValue := func(replicas int32) bool {
+		return replicas == CronJobSuspended || replicas == CronJobActive
+	}
+	if !validValue(r.OriginalReplicas) {
+		return fmt.Errorf("original replicas must be 0 (suspended) or 1 (active) for a CronJob target")
+	}
+	if r.HolidayReplicas != nil && !validValue(*r.HolidayReplicas) {
+		return fmt.Errorf("holiday replicas must be 0 (suspended) or 1 (active) for a CronJob target")
+	}
+	if r.IsHPAMode() {
+		return fmt.Errorf("scale mode %q is not supported for a CronJob target", ScaleModeHPA)
+	}
+	for i, window := range r.Windows {
+		if !validValue(window.Replicas) {
+			return fmt.Errorf("resource %s/%s: window %d is invalid: replicas must be 0 (suspended) or 1 (active) for a CronJob target",
+				r.Namespace, r.Name, i)
+		}
+		if window.Ramp != nil {
+			return fmt.Errorf("resource %s/%s: window %d is invalid: ramp is not supported for a CronJob target",
+				r.Namespace, r.Name, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package model
+
+import "fmt"
+
+// Scale modes decide how a resource's schedule is applied to its target
+const (
+	// ScaleModeReplicas scales the target through its /scale subresource (the default)
+	ScaleModeReplicas = "replicas"
+	// ScaleModeHPA adjusts the minReplicas and maxReplicas of the target's HorizontalPodAutoscaler
+	ScaleModeHPA = "hpa"
+)
+
+// IsHPAMode reports whether the resource adjusts an autoscaler rather than the target
+func (r *Resource) IsHPAMode() bool {
+	return r.ScaleMode == ScaleModeHPA
+}
+
+// validateScaleMode checks the scale mode and the settings that depend on it
+func (r *Resource) validateScaleMode() error {
+	switch r.ScaleMode {
+	case "", ScaleModeReplicas:
+		if r.HPAName != "" {
+			return fmt.Errorf("hpa name requires scale mode %q", ScaleModeHPA)
+		}
+		for i, window := range r.Windows {
+			if window.MaxReplicas != nil {
+				return fmt.Errorf("resource %s/%s: window %d is invalid: max replicas requires scale mode %q",
+					r.Namespace, r.Name, i, ScaleModeHPA)
+			}
+		}
+		return nil
+	case ScaleModeHPA:
+	default:
+		return fmt.Errorf("unknown scale mode %q", r.ScaleMode)
+	}
+
+	if r.CaptureBaseline {
+		return fmt.Errorf("capture baseline is not supported in scale mode %q", ScaleModeHPA)
+	}
+	if r.HolidayReplicas != nil && *r.HolidayReplicas < 1 {
+		return fmt.Errorf("holiday replicas must be at least 1 in scale mode %q", ScaleModeHPA)
+	}
+	for i, window := range r.Windows {
+		// An autoscaler's minReplicas cannot be zero unless its alpha feature gate is on
+		if window.Replicas < 1 {
+			return fmt.Errorf("resource %s/%s: window %d is invalid: replicas must be at least 1 in scale mode %q",
+				r.Namespace, r.Name, i, ScaleModeHPA)
+		}
+		if window.Ramp != nil {
+			return fmt.Errorf("resource %s/%s: window %d is invalid: ramp is not supported in scale mode %q",
+				r.Namespace, r.Name, i, ScaleModeHPA)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Overlap policies decide which window applies when several are active at once
+const (
+	// OverlapPolicyFirst uses the first active window in slice order (the default)
+	OverlapPolicyFirst = "first"
+	// OverlapPolicyHighestPriority uses the active window with the highest priority
+	OverlapPolicyHighestPriority = "highestPriority"
+	// OverlapPolicyMaxReplicas uses the active window with the most replicas
+	OverlapPolicyMaxReplicas = "maxReplicas"
+	// OverlapPolicyMinReplicas uses the active window with the fewest replicas
+	OverlapPolicyMinReplicas = "minReplicas"
+)
+
+const (
+	// overlapHorizon is how far ahead recurring and cron windows are checked for overlaps
+	overlapHorizon = 7 * 24 * time.Hour
+	// maxOccurrences bounds how many cron activations are expanded per window
+	maxOccurrences = 1000
+)
+
+func validOverlapPolicy(policy string) bool {
+	switch policy {
+	case "", OverlapPolicyFirst, OverlapPolicyHighestPriority, OverlapPolicyMaxReplicas, OverlapPolicyMinReplicas:
+		return true
+	}
+	return false
+}
+
+// prefers reports whether candidate should replace current under the resource's
+// overlap policy. Ties keep current, so earlier windows win.
+func (r *Resource) prefers(candidate, current *ScalingWindow) bool {
+	switch r.OverlapPolicy {
+	case OverlapPolicyHighestPriority:
+		return candidate.Priority > current.Priority
+	case OverlapPolicyMaxReplicas:
+		return candidate.Replicas > current.Replicas
+	case OverlapPolicyMinReplicas:
+		return candidate.Replicas < current.Replicas
+	default:
+		return false
+	}
+}
+
+// OverlapWarnings describes pairs of windows that overlap without a defined
+// resolution: no overlapPolicy is set, or highestPriority is set and the windows
+// share a priority. Absolute windows are compared until they end, ignoring those
+// that ended before now, recurring and cron windows over the week following now.
+// Overlapping windows with the same replica count are not reported since the outcome
+// does not depend on the resolution. Validate records the warnings in Warnings.
+func (r *Resource) OverlapWarnings(now int64) []string {
+	if r.OverlapPolicy != "" && r.OverlapPolicy != OverlapPolicyHighestPriority {
+		return nil
+	}
+
+	from := time.Unix(now, 0).In(r.Location())
+	to := from.Add(overlapHorizon)
+
+	occurrences := make([][]interval, len(r.Windows))
+	for i := range r.Windows {
+		occurrences[i] = r.Windows[i].occurrences(from, to)
+	}
+
+	var warnings []string
+	for i := range r.Windows {
+		for j := i + 1; j < len(r.Windows); j++ {
+			a, b := &r.Windows[i], &r.Windows[j]
+			if a.Replicas == b.Replicas || !intervalsOverlap(occurrences[i], occurrences[j]) {
+				continue
+			}
+			switch {
+			case r.OverlapPolicy == "":
+				warnings = append(warnings, fmt.Sprintf(
+					"windows %d and %d overlap and no overlapPolicy is set, window %d takes precedence", i, j, i))
+			case a.Priority == b.Priority:
+				warnings = append(warnings, fmt.Sprintf(
+					"windows %d and %d overlap with equal priority %d, window %d takes precedence", i, j, a.Priority, i))
+			}
+		}
+	}
+	return warnings
+}
+
+// interval is a half-open time range [start, end)
+type interval struct {
+	start, end time.Time
+}
+
+// occurrences returns the intervals, sorted by start, during which the window is
+// active between from and to. Absolute windows return their full range unless it
+// ended before from.
+func (w *ScalingWindow) occurrences(from, to time.Time) []interval {
+	switch {
+	case w.IsCron():
+		schedule, duration, err := w.parseCron()
+		if err != nil {
+			return nil
+		}
+		var result []interval
+		activations(schedule, wallClock(from).Add(-duration-2*maxZoneShift), wallClock(to).Add(maxZoneShift), from.Location(), func(start time.Time) bool {
+			if end := start.Add(duration); end.After(from) && start.Before(to) {
+				result = append(result, interval{start: start, end: end})
+			}
+			return len(result) < maxOccurrences
+		})
+		return result
+	case w.IsRecurring():
+		rc, err := w.parseRecurrence()
+		if err != nil {
+			return nil
+		}
+		var result []interval
+		year, month, day := from.Date()
+		// Start a day early to catch a window running past midnight into from
+		for date := time.Date(year, month, day-1, 12, 0, 0, 0, from.Location()); date.Before(to.AddDate(0, 0, 1)); date = date.AddDate(0, 0, 1) {
+			start, end, ok := rc.occurrence(date)
+			if ok && end.After(from) && start.Before(to) {
+				result = append(result, interval{start: start, end: end})
+			}
+		}
+		return result
+	default:
+		end := time.Unix(w.EndTime, 0)
+		if !end.After(from) {
+			return nil
+		}
+		return []interval{{start: time.Unix(w.StartTime, 0), end: end}}
+	}
+}
+
+// intervalsOverlap reports whether any interval in a intersects any in b.
+// Both slices must be sorted by start and by end.
+func intervalsOverlap(a, b []interval) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i].start.Before(b[j].end) && b[j].start.Before(a[i].end) {
+			return true
+		}
+		if a[i].end.Before(b[j].end) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResource_GetDesiredReplicas_OverlapPolicy(t *testing.T) {
+	now := time.Now().Unix()
+	windows := []ScalingWindow{
+		{StartTime: now - 50, EndTime: now + 50, Replicas: 5, Priority: 1},
+		{StartTime: now - 40, EndTime: now + 40, Replicas: 8, Priority: 3},
+		{StartTime: now - 30, EndTime: now + 30, Replicas: 3, Priority: 3},
+		{StartTime: now + 10, EndTime: now + 90, Replicas: 1, Priority: 9},
+	}
+
+	tests := []struct {
+		name   string
+		policy string
+		want   int32
+	}{
+		{name: "default uses first active window", policy: "", want: 5},
+		{name: "first uses first active window", policy: OverlapPolicyFirst, want: 5},
+		{name: "highest priority prefers earlier window on ties", policy: OverlapPolicyHighestPriority, want: 8},
+		{name: "max replicas", policy: OverlapPolicyMaxReplicas, want: 8},
+		{name: "min replicas ignores inactive windows", policy: OverlapPolicyMinReplicas, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := Resource{
+				Name:             "test-resource",
+				OriginalReplicas: 2,
+				OverlapPolicy:    tt.policy,
+				Windows:          windows,
+			}
+			if got := resource.GetDesiredReplicas(now); got != tt.want {
+				t.Errorf("Resource.GetDesiredReplicas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResource_OverlapWarnings(t *testing.T) {
+	// 2024-01-01 is a Monday
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	tests := []struct {
+		name         string
+		policy       string
+		windows      []ScalingWindow
+		wantWarnings int
+		wantContains string
+	}{
+		{
+			name:   "absolute windows overlap without policy",
+			policy: "",
+			windows: []ScalingWindow{
+				{StartTime: now + 100, EndTime: now + 200, Replicas: 3},
+				{StartTime: now + 150, EndTime: now + 250, Replicas: 5},
+			},
+			wantWarnings: 1,
+			wantContains: "windows 0 and 1 overlap and no overlapPolicy is set",
+		},
+		{
+			name:   "ended windows are ignored",
+			policy: "",
+			windows: []ScalingWindow{
+				{StartTime: now - 300, EndTime: now - 100, Replicas: 3},
+				{StartTime: now - 200, EndTime: now, Replicas: 5},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:   "adjacent windows do not overlap",
+			policy: "",
+			windows: []ScalingWindow{
+				{StartTime: now + 100, EndTime: now + 200, Replicas: 3},
+				{StartTime: now + 200, EndTime: now + 300, Replicas: 5},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:   "overlap with same replicas is harmless",
+			policy: "",
+			windows: []ScalingWindow{
+				{StartTime: now + 100, EndTime: now + 200, Replicas: 3},
+				{StartTime: now + 150, EndTime: now + 250, Replicas: 3},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:   "explicit policy resolves overlap",
+			policy: OverlapPolicyMaxReplicas,
+			windows: []ScalingWindow{
+				{StartTime: now + 100, EndTime: now + 200, Replicas: 3},
+				{StartTime: now + 150, EndTime: now + 250, Replicas: 5},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:   "equal priorities under highest priority",
+			policy: OverlapPolicyHighestPriority,
+			windows: []ScalingWindow{
+				{StartTime: now + 100, EndTime: now + 200, Replicas: 3, Priority: 2},
+				{StartTime: now + 150, EndTime: now + 250, Replicas: 5, Priority: 2},
+			},
+			wantWarnings: 1,
+			wantContains: "equal priority 2",
+		},
+		{
+			name:   "distinct priorities under highest priority",
+			policy: OverlapPolicyHighestPriority,
+			windows: []ScalingWindow{
+				{StartTime: now + 100, EndTime: now + 200, Replicas: 3, Priority: 1},
+				{StartTime: now + 150, EndTime: now + 250, Replicas: 5, Priority: 2},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:   "recurring windows on shared days",
+			policy: "",
+			windows: []ScalingWindow{
+				{Days: []string{"Mon-Fri"}, StartTimeOfDay: "08:00", EndTimeOfDay: "18:00", Replicas: 5},
+				{Days: []string{"Fri"}, StartTimeOfDay: "17:00", EndTimeOfDay: "22:00", Replicas: 2},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:   "recurring windows on different days",
+			policy: "",
+			windows: []ScalingWindow{
+				{Days: []string{"Mon-Fri"}, StartTimeOfDay: "08:00", EndTimeOfDay: "18:00", Replicas: 5},
+				{Days: []string{"Sat", "Sun"}, StartTimeOfDay: "08:00", EndTimeOfDay: "18:00", Replicas: 2},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:   "overnight recurring window overlaps next morning",
+			policy: "",
+			windows: []ScalingWindow{
+				{Days: []string{"Sun"}, StartTimeOfDay: "22:00", EndTimeOfDay: "09:00", Replicas: 1},
+				{Days: []string{"Mon"}, StartTimeOfDay: "08:00", EndTimeOfDay: "18:00", Replicas: 5},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:   "cron window overlaps recurring window",
+			policy: "",
+			windows: []ScalingWindow{
+				{Schedule: "0 12 * * 3", Duration: "2h", Replicas: 1},
+				{Days: []string{"Mon-Fri"}, StartTimeOfDay: "08:00", EndTimeOfDay: "18:00", Replicas: 5},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name:   "cron window that never fires",
+			policy: "",
+			windows: []ScalingWindow{
+				{Schedule: "0 0 30 2 *", Duration: "24h", Replicas: 1},
+				{Days: []string{"Mon-Sun"}, StartTimeOfDay: "00:00", EndTimeOfDay: "24:00", Replicas: 5},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name:   "cron window outside recurring window",
+			policy: "",
+			windows: []ScalingWindow{
+				{Schedule: "0 20 * * *", Duration: "2h", Replicas: 1},
+				{Days: []string{"Mon-Fri"}, StartTimeOfDay: "08:00", EndTimeOfDay: "18:00", Replicas: 5},
+			},
+			wantWarnings: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := Resource{
+				Name:             "test-resource",
+				OriginalReplicas: 2,
+				OverlapPolicy:    tt.policy,
+				Windows:          tt.windows,
+			}
+			warnings := resource.OverlapWarnings(now)
+			if len(warnings) != tt.wantWarnings {
+				t.Fatalf("Resource.OverlapWarnings() = %v, want %d warnings", warnings, tt.wantWarnings)
+			}
+			if tt.wantContains != "" && !contains(warnings[0], tt.wantContains) {
+				t.Errorf("Resource.OverlapWarnings() = %v, should contain %v", warnings[0], tt.wantContains)
+			}
+		})
+	}
+}
+
+func TestResource_Validate_OverlapWarnings(t *testing.T) {
+	now := time.Now().Unix()
+	resource := Resource{
+		Name:             "test-resource",
+		Namespace:        "default",
+		Target:           Target{Name: "test", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Windows: []ScalingWindow{
+			{StartTime: now - 3600, EndTime: now + 3600, Replicas: 3},
+			{StartTime: now, EndTime: now + 7200, Replicas: 5},
+		},
+	}
+	if err := resource.Validate(); err != nil {
+		t.Fatalf("Resource.Validate() error = %v", err)
+	}
+	if len(resource.Warnings) != 1 || !contains(resource.Warnings[0], "windows 0 and 1 overlap") {
+		t.Errorf("Resource.Warnings after Validate() = %v, want the overlap of windows 0 and 1", resource.Warnings)
+	}
+
+	resource.OverlapPolicy = OverlapPolicyMaxReplicas
+	if err := resource.Validate(); err != nil {
+		t.Fatalf("Resource.Validate() error = %v", err)
+	}
+	if len(resource.Warnings) != 0 {
+		t.Errorf("Resource.Warnings with an overlap policy = %v, want none", resource.Warnings)
+	}
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kinds of provider a resource can be loaded by
+const (
+	ProviderFile        = "file"
+	ProviderRemote      = "remote"
+	ProviderGit         = "git"
+	ProviderObjectStore = "s3"
+	ProviderCRD         = "crd"
+	ProviderConfigMap   = "configmap"
+)
+
+// Provenance records where a resource's configuration was loaded from
+type Provenance struct {
+	// Provider is the kind of provider that loaded the resource, such as ProviderFile
+	Provider string
+	// Source is the file, URL, object or Kubernetes object the resource was read from
+	Source string
+	// Revision identifies the version of the source: a content hash, ETag, commit SHA
+	// or resourceVersion
+	Revision string
+	// LoadedAt is when the source was read
+	LoadedAt time.Time
+}
+
+// String describes the provenance for logs and events, abbreviating long revisions
+func (p *Provenance) String() string {
+	description := p.Provider + " " + p.Source
+	var details []string
+	if revision := strings.Trim(p.Revision, `"`); revision != "" {
+		details = append(details, fmt.Sprintf("revision %.12s", revision))
+	}
+	if !p.LoadedAt.IsZero() {
+		details = append(details, "loaded "+p.LoadedAt.UTC().Format(time.RFC3339))
+	}
+	if len(details) > 0 {
+		description += " (" + strings.Join(details, ", ") + ")"
+	}
+	return description
+}
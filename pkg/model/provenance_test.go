@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProvenance_String(t *testing.T) {
+	loadedAt := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		provenance Provenance
+		want       string
+	}{
+		{
+			name: "commit",
+			provenance: Provenance{Provider: ProviderGit, Source: "https://git/schedules.git@main:web.yaml",
+				Revision: "4f2c9a1b7e0d3c5a8b6f", LoadedAt: loadedAt},
+			want: "git https://git/schedules.git@main:web.yaml (revision 4f2c9a1b7e0d, loaded 2025-01-06T09:00:00Z)",
+		},
+		{
+			name:       "quoted ETag",
+			provenance: Provenance{Provider: ProviderRemote, Source: "https://config", Revision: `"v1"`, LoadedAt: loadedAt},
+			want:       "remote https://config (revision v1, loaded 2025-01-06T09:00:00Z)",
+		},
+		{
+			name:       "no revision or load time",
+			provenance: Provenance{Provider: ProviderCRD, Source: "ScheduledResource default/web"},
+			want:       "crd ScheduledResource default/web",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.provenance.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
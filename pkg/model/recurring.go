@@ -0,0 +1,183 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// parseWeekday parses a day name such as "Mon" or "monday"
+func parseWeekday(s string) (time.Weekday, error) {
+	day, ok := weekdayNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("invalid day of week %q", s)
+	}
+	return day, nil
+}
+
+// parseDays expands a list of day names and ranges ("Mon-Fri") into a set of weekdays
+func parseDays(days []string) ([7]bool, error) {
+	var set [7]bool
+	for _, entry := range days {
+		from, to, isRange := strings.Cut(entry, "-")
+		start, err := parseWeekday(from)
+		if err != nil {
+			return set, err
+		}
+		end := start
+		if isRange {
+			if end, err = parseWeekday(to); err != nil {
+				return set, err
+			}
+		}
+		// Ranges may wrap around the week, e.g. "Sat-Mon"
+		for d := start; ; d = (d + 1) % 7 {
+			set[d] = true
+			if d == end {
+				break
+			}
+		}
+	}
+	return set, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into seconds since midnight. "24:00" is accepted
+// so that a window can run until the end of the day.
+func parseTimeOfDay(s string) (int64, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	hours, err := strconv.Atoi(hh)
+	if err != nil || len(hh) != 2 {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	minutes, err := strconv.Atoi(mm)
+	if err != nil || len(mm) != 2 {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("time of day %q is out of range", s)
+	}
+	return int64(hours*3600 + minutes*60), nil
+}
+
+// wallTime returns the instant at which the wall clock in loc reads sec seconds
+// after midnight on the given date. Wall times inside a spring-forward gap do not
+// exist; they resolve to the end of the gap rather than to the pre-transition
+// offset as time.Date does. Ambiguous fall-back times resolve to their first occurrence.
+func wallTime(year int, month time.Month, day int, sec int64, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, 0, 0, int(sec), 0, loc)
+	want := time.Date(year, month, day, 0, 0, int(sec), 0, time.UTC)
+	if t.Day() == want.Day() && t.Hour() == want.Hour() && t.Minute() == want.Minute() {
+		return t
+	}
+	_, transition := t.ZoneBounds()
+	return transition
+}
+
+// recurrence is the parsed form of a recurring window
+type recurrence struct {
+	days     [7]bool
+	startSec int64
+	length   int64
+}
+
+// parseRecurrence parses the recurring fields of a window
+func (w *ScalingWindow) parseRecurrence() (recurrence, error) {
+	days, err := parseDays(w.Days)
+	if err != nil {
+		return recurrence{}, err
+	}
+	startSec, err := parseTimeOfDay(w.StartTimeOfDay)
+	if err != nil {
+		return recurrence{}, err
+	}
+	endSec, err := parseTimeOfDay(w.EndTimeOfDay)
+	if err != nil {
+		return recurrence{}, err
+	}
+	length := endSec - startSec
+	if length <= 0 {
+		length += secondsPerDay
+	}
+	return recurrence{days: days, startSec: startSec, length: length}, nil
+}
+
+// occurrence returns the window starting on the calendar day of date, if the
+// recurrence applies to that day
+func (rc recurrence) occurrence(date time.Time) (start, end time.Time, ok bool) {
+	if !rc.days[date.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+	y, m, d := date.Date()
+	start = wallTime(y, m, d, rc.startSec, date.Location())
+	end = wallTime(y, m, d, rc.startSec+rc.length, date.Location())
+	// A window that falls entirely into a DST gap keeps its nominal length
+	if !end.After(start) {
+		end = start.Add(time.Duration(rc.length) * time.Second)
+	}
+	return start, end, true
+}
+
+// isRecurringActive reports whether a recurring window is active at t, evaluated
+// on the wall clock of t's location. A window whose end is before its start spans
+// midnight and belongs to the day it starts on.
+func (w *ScalingWindow) isRecurringActive(t time.Time) bool {
+	rc, err := w.parseRecurrence()
+	if err != nil {
+		return false
+	}
+
+	year, month, day := t.Date()
+	// Only a window starting today or yesterday can cover t
+	for _, offset := range []int{0, -1} {
+		date := time.Date(year, month, day+offset, 12, 0, 0, 0, t.Location())
+		start, end, ok := rc.occurrence(date)
+		if ok && !t.Before(start) && t.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRecurring checks the recurring fields of a window
+func (w *ScalingWindow) validateRecurring() error {
+	if w.StartTime != 0 || w.EndTime != 0 {
+		return fmt.Errorf("recurring windows cannot also set startTime or endTime")
+	}
+	if len(w.Days) == 0 {
+		return fmt.Errorf("recurring windows require at least one day")
+	}
+	if _, err := parseDays(w.Days); err != nil {
+		return err
+	}
+	start, err := parseTimeOfDay(w.StartTimeOfDay)
+	if err != nil {
+		return fmt.Errorf("invalid start time of day: %w", err)
+	}
+	end, err := parseTimeOfDay(w.EndTimeOfDay)
+	if err != nil {
+		return fmt.Errorf("invalid end time of day: %w", err)
+	}
+	if start == secondsPerDay {
+		return fmt.Errorf("start time of day cannot be 24:00")
+	}
+	if start == end {
+		return fmt.Errorf("start time of day must differ from end time of day")
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package model
 
 import (
 	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // Resource represents a Kubernetes resource with time-based scaling configuration
@@ -14,50 +17,258 @@ type Resource struct {
 	Target Target `json:"target" yaml:"target"`
 	// OriginalReplicas is the base number of replicas to return to when no window is active
 	OriginalReplicas int32 `json:"originalReplicas" yaml:"originalReplicas"`
+	// TimeZone is the IANA zone recurring and cron windows are evaluated in, defaults to UTC
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
 	// Windows defines the time windows for scaling
 	Windows []ScalingWindow `json:"windows" yaml:"windows"`
+	// Calendar names a holiday calendar on whose days the windows are inactive
+	Calendar string `json:"calendar,omitempty" yaml:"calendar,omitempty"`
+	// CaptureBaseline records the target's live replica count when a window starts and
+	// restores it when the window ends, instead of using OriginalReplicas
+	CaptureBaseline bool `json:"captureBaseline,omitempty" yaml:"captureBaseline,omitempty"`
+	// OverlapPolicy decides which window applies when several are active:
+	// "first" (default), "highestPriority", "maxReplicas" or "minReplicas"
+	OverlapPolicy string `json:"overlapPolicy,omitempty" yaml:"overlapPolicy,omitempty"`
+	// HolidayReplicas, if set, is used instead of OriginalReplicas on calendar days
+	HolidayReplicas *int32 `json:"holidayReplicas,omitempty" yaml:"holidayReplicas,omitempty"`
+	// ScaleMode is "replicas" (default) to scale the target directly, or "hpa" to
+	// adjust the bounds of the HorizontalPodAutoscaler managing it
+	ScaleMode string `json:"scaleMode,omitempty" yaml:"scaleMode,omitempty"`
+	// HPAName names the autoscaler adjusted in hpa mode, defaults to the one targeting Target
+	HPAName string `json:"hpaName,omitempty" yaml:"hpaName,omitempty"`
+	// Holidays is the resolved calendar referenced by Calendar, filled in by the scheduler
+	Holidays *Calendar `json:"-" yaml:"-"`
+	// Stale, if set, marks configuration served past its provider's maximum staleness,
+	// filled in by the provider
+	Stale *Staleness `json:"-" yaml:"-"`
+	// Provenance records where the resource was loaded from, filled in by the provider
+	Provenance *Provenance `json:"-" yaml:"-"`
+	// Warnings describes problems that do not make the resource invalid, such as
+	// windows overlapping without a defined resolution, filled in by Validate
+	Warnings []string `json:"-" yaml:"-"`
+
+	// location caches the zone named by TimeZone, resolved by Validate
+	location *time.Location
 }
 
 // Target defines the Kubernetes resource to be scaled
 type Target struct {
 	// Name of the target resource
 	Name string `json:"name" yaml:"name"`
-	// Kind of the target resource ("Deployment" OR "StatefulSet")
+	// Kind of the target resource, any kind exposing the /scale subresource, or CronJob
 	Kind string `json:"kind" yaml:"kind"`
-	// APIVersion of the target resource
+	// APIVersion of the target resource, optional for Deployment, StatefulSet, ReplicaSet and CronJob
 	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
 }
 
-// ScalingWindow defines a time window for scaling
+// defaultAPIVersions are assumed for built-in kinds when Target.APIVersion is empty
+var defaultAPIVersions = map[string]string{
+	"Deployment":  "apps/v1",
+	"StatefulSet": "apps/v1",
+	"ReplicaSet":  "apps/v1",
+	"CronJob":     "batch/v1",
+}
+
+// GroupVersionKind returns the target's group, version and kind
+func (t *Target) GroupVersionKind() (schema.GroupVersionKind, error) {
+	apiVersion := t.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAPIVersions[t.Kind]
+	}
+	if apiVersion == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("target apiVersion is required for kind %s", t.Kind)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid target apiVersion %q: %w", apiVersion, err)
+	}
+	return gv.WithKind(t.Kind), nil
+}
+
+// ScalingWindow defines a time window for scaling. A window is either absolute
+// (StartTime/EndTime as Unix seconds), recurring (Days plus a time-of-day range)
+// or a cron expression (Schedule plus Duration).
 type ScalingWindow struct {
-	StartTime int64 `json:"startTime" yaml:"startTime"`
-	EndTime   int64 `json:"endTime" yaml:"endTime"`
-	Replicas  int32 `json:"replicas" yaml:"replicas"`
+	StartTime int64 `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	EndTime   int64 `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+	// Days the recurring window applies to, e.g. ["Mon-Fri"] or ["Sat", "Sun"]
+	Days []string `json:"days,omitempty" yaml:"days,omitempty"`
+	// StartTimeOfDay and EndTimeOfDay bound a recurring window ("HH:MM" local time).
+	// If the end is before the start, the window runs past midnight.
+	StartTimeOfDay string `json:"startTimeOfDay,omitempty" yaml:"startTimeOfDay,omitempty"`
+	EndTimeOfDay   string `json:"endTimeOfDay,omitempty" yaml:"endTimeOfDay,omitempty"`
+	// Schedule is a five-field cron expression marking when the window opens (local time)
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// Duration is how long a cron window stays open, e.g. "10h"
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Replicas int32  `json:"replicas" yaml:"replicas"`
+	// Priority ranks overlapping windows under the highestPriority overlap policy
+	Priority int32 `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Ramp, if set, moves the target to Replicas gradually instead of in one step
+	Ramp *RampPolicy `json:"ramp,omitempty" yaml:"ramp,omitempty"`
+	// MaxReplicas sets the autoscaler's maxReplicas in hpa mode; Replicas sets its minReplicas
+	MaxReplicas *int32 `json:"maxReplicas,omitempty" yaml:"maxReplicas,omitempty"`
+}
+
+// RampPolicy controls how a window moves the target from its current replica count
+// to the window's replicas. StepSize caps the change per step, Duration spreads the
+// change evenly over a period, and StepInterval sets the minimum time between steps.
+type RampPolicy struct {
+	// StepSize is the maximum number of replicas added or removed per step
+	StepSize int32 `json:"stepSize,omitempty" yaml:"stepSize,omitempty"`
+	// StepInterval is the minimum time between steps, e.g. "2m"; defaults to the poll interval
+	StepInterval string `json:"stepInterval,omitempty" yaml:"stepInterval,omitempty"`
+	// Duration is how long the whole ramp should take, e.g. "30m"
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+}
+
+// Parse returns the ramp's step interval and duration, zero when unset
+func (p *RampPolicy) Parse() (stepInterval, duration time.Duration, err error) {
+	if p.StepInterval != "" {
+		if stepInterval, err = time.ParseDuration(p.StepInterval); err != nil {
+			return 0, 0, fmt.Errorf("invalid ramp step interval %q: %w", p.StepInterval, err)
+		}
+		if stepInterval <= 0 {
+			return 0, 0, fmt.Errorf("ramp step interval must be positive")
+		}
+	}
+	if p.Duration != "" {
+		if duration, err = time.ParseDuration(p.Duration); err != nil {
+			return 0, 0, fmt.Errorf("invalid ramp duration %q: %w", p.Duration, err)
+		}
+		if duration <= 0 {
+			return 0, 0, fmt.Errorf("ramp duration must be positive")
+		}
+	}
+	return stepInterval, duration, nil
+}
+
+func (p *RampPolicy) Validate() error {
+	if p.StepSize < 0 {
+		return fmt.Errorf("ramp step size cannot be negative")
+	}
+	if p.StepSize == 0 && p.Duration == "" {
+		return fmt.Errorf("ramp requires a step size or a duration")
+	}
+	_, _, err := p.Parse()
+	return err
+}
+
+// IsCron reports whether the window is driven by a cron expression
+func (w *ScalingWindow) IsCron() bool {
+	return w.Schedule != ""
+}
+
+// IsRecurring reports whether the window uses the weekly recurring form
+func (w *ScalingWindow) IsRecurring() bool {
+	return len(w.Days) > 0 || w.StartTimeOfDay != "" || w.EndTimeOfDay != ""
 }
 
+// IsActive reports whether the window is active at now, evaluating recurring and cron windows in UTC
 func (w *ScalingWindow) IsActive(now int64) bool {
+	return w.IsActiveIn(now, time.UTC)
+}
+
+// IsActiveIn reports whether the window is active at now, evaluating recurring and cron windows in loc
+func (w *ScalingWindow) IsActiveIn(now int64, loc *time.Location) bool {
+	if w.IsCron() {
+		return w.isCronActive(time.Unix(now, 0).In(loc))
+	}
+	if w.IsRecurring() {
+		return w.isRecurringActive(time.Unix(now, 0).In(loc))
+	}
 	return now >= w.StartTime && now < w.EndTime
 }
 
 func (w *ScalingWindow) Validate() error {
-	if w.StartTime >= w.EndTime {
+	if w.IsCron() {
+		if err := w.validateCron(); err != nil {
+			return err
+		}
+	} else if w.Duration != "" {
+		return fmt.Errorf("duration requires a cron schedule")
+	} else if w.IsRecurring() {
+		if err := w.validateRecurring(); err != nil {
+			return err
+		}
+	} else if w.StartTime >= w.EndTime {
 		return fmt.Errorf("start time must be before end time")
 	}
 	if w.Replicas < 0 {
 		return fmt.Errorf("replicas cannot be negative")
 	}
+	if w.Ramp != nil {
+		if err := w.Ramp.Validate(); err != nil {
+			return err
+		}
+	}
+	if w.MaxReplicas != nil && *w.MaxReplicas < w.Replicas {
+		return fmt.Errorf("max replicas cannot be less than replicas")
+	}
 	return nil
 }
 
+// Location returns the resource's time zone, falling back to UTC when unset or invalid.
+// The zone resolved by Validate is reused as long as TimeZone still names it.
+func (r *Resource) Location() *time.Location {
+	if r.TimeZone == "" {
+		return time.UTC
+	}
+	if r.location != nil && r.location.String() == r.TimeZone {
+		return r.location
+	}
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 func (r *Resource) GetDesiredReplicas(now int64) int32 {
-	for _, window := range r.Windows {
-		if window.IsActive(now) {
-			return window.Replicas
+	if r.IsHoliday(now) {
+		if r.HolidayReplicas != nil {
+			return *r.HolidayReplicas
 		}
+		return r.OriginalReplicas
+	}
+
+	if window := r.ActiveWindow(now); window != nil {
+		return window.Replicas
 	}
 	return r.OriginalReplicas
 }
 
+// ActiveWindow returns the window that applies at now under the overlap policy, or nil.
+// Calendar days are not taken into account.
+func (r *Resource) ActiveWindow(now int64) *ScalingWindow {
+	loc := r.Location()
+	var selected *ScalingWindow
+	for i := range r.Windows {
+		window := &r.Windows[i]
+		if !window.IsActiveIn(now, loc) {
+			continue
+		}
+		if selected == nil || r.prefers(window, selected) {
+			selected = window
+		}
+	}
+	return selected
+}
+
+// IsScheduled reports whether the desired replicas at now come from a window or
+// holiday replicas rather than the baseline
+func (r *Resource) IsScheduled(now int64) bool {
+	if r.IsHoliday(now) {
+		return r.HolidayReplicas != nil
+	}
+	return r.ActiveWindow(now) != nil
+}
+
+// IsHoliday reports whether now falls on a day of the resource's resolved calendar
+func (r *Resource) IsHoliday(now int64) bool {
+	return r.Holidays != nil && r.Holidays.Contains(time.Unix(now, 0).In(r.Location()))
+}
+
 func (r *Resource) Validate() error {
 	if r.Name == "" {
 		return fmt.Errorf("resource name is required")
@@ -71,16 +282,46 @@ func (r *Resource) Validate() error {
 	if r.Target.Kind == "" {
 		return fmt.Errorf("target kind is required")
 	}
+	if _, err := r.Target.GroupVersionKind(); err != nil {
+		return err
+	}
 	if r.OriginalReplicas < 0 {
 		return fmt.Errorf("original replicas cannot be negative")
 	}
+	if r.TimeZone != "" && (r.location == nil || r.location.String() != r.TimeZone) {
+		loc, err := time.LoadLocation(r.TimeZone)
+		if err != nil {
+			return fmt.Errorf("invalid time zone %q: %w", r.TimeZone, err)
+		}
+		r.location = loc
+	}
+
+	if !validOverlapPolicy(r.OverlapPolicy) {
+		return fmt.Errorf("unknown overlap policy %q", r.OverlapPolicy)
+	}
+	if r.HolidayReplicas != nil {
+		if r.Calendar == "" {
+			return fmt.Errorf("holiday replicas require a calendar")
+		}
+		if *r.HolidayReplicas < 0 {
+			return fmt.Errorf("holiday replicas cannot be negative")
+		}
+	}
 
 	// Validate all windows
 	for i, window := range r.Windows {
 		if err := window.Validate(); err != nil {
-			return fmt.Errorf("window %d is invalid: %w", i, err)
+			return fmt.Errorf("resource %s/%s: window %d is invalid: %w", r.Namespace, r.Name, i, err)
 		}
 	}
 
+	if err := r.validateScaleMode(); err != nil {
+		return err
+	}
+	if err := r.validateCronJob(); err != nil {
+		return err
+	}
+
+	r.Warnings = r.OverlapWarnings(time.Now().Unix())
 	return nil
 }
@@ -74,6 +74,152 @@ func TestScalingWindow_IsActive(t *testing.T) {
 	}
 }
 
+func TestScalingWindow_IsActive_Recurring(t *testing.T) {
+	// 2024-01-01 is a Monday
+	at := func(day int, hour, minute int) int64 {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC).Unix()
+	}
+
+	businessHours := ScalingWindow{
+		Days:           []string{"Mon-Fri"},
+		StartTimeOfDay: "08:00",
+		EndTimeOfDay:   "18:00",
+		Replicas:       5,
+	}
+	overnight := ScalingWindow{
+		Days:           []string{"Fri"},
+		StartTimeOfDay: "22:00",
+		EndTimeOfDay:   "06:00",
+		Replicas:       1,
+	}
+	weekend := ScalingWindow{
+		Days:           []string{"Sat", "sunday"},
+		StartTimeOfDay: "00:00",
+		EndTimeOfDay:   "24:00",
+		Replicas:       0,
+	}
+
+	tests := []struct {
+		name     string
+		window   ScalingWindow
+		now      int64
+		expected bool
+	}{
+		{name: "weekday within hours", window: businessHours, now: at(1, 9, 30), expected: true},
+		{name: "weekday at start boundary", window: businessHours, now: at(1, 8, 0), expected: true},
+		{name: "weekday at end boundary", window: businessHours, now: at(1, 18, 0), expected: false},
+		{name: "weekday before hours", window: businessHours, now: at(3, 7, 59), expected: false},
+		{name: "weekend within hours", window: businessHours, now: at(6, 12, 0), expected: false},
+		{name: "overnight on start day", window: overnight, now: at(5, 23, 0), expected: true},
+		{name: "overnight after midnight", window: overnight, now: at(6, 5, 59), expected: true},
+		{name: "overnight ended", window: overnight, now: at(6, 6, 0), expected: false},
+		{name: "overnight wrong day", window: overnight, now: at(4, 23, 0), expected: false},
+		{name: "full day saturday", window: weekend, now: at(6, 23, 59), expected: true},
+		{name: "full day monday", window: weekend, now: at(1, 0, 0), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.IsActive(tt.now); got != tt.expected {
+				t.Errorf("ScalingWindow.IsActive() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScalingWindow_IsActive_Cron(t *testing.T) {
+	// 2024-01-01 is a Monday
+	at := func(day int, hour, minute int) int64 {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC).Unix()
+	}
+
+	weekdays := ScalingWindow{Schedule: "0 8 * * 1-5", Duration: "10h", Replicas: 5}
+	overnight := ScalingWindow{Schedule: "0 22 * * 5", Duration: "8h", Replicas: 1}
+
+	tests := []struct {
+		name     string
+		window   ScalingWindow
+		now      int64
+		expected bool
+	}{
+		{name: "at activation", window: weekdays, now: at(1, 8, 0), expected: true},
+		{name: "within duration", window: weekdays, now: at(2, 17, 59), expected: true},
+		{name: "at end of duration", window: weekdays, now: at(2, 18, 0), expected: false},
+		{name: "before activation", window: weekdays, now: at(2, 7, 59), expected: false},
+		{name: "weekend", window: weekdays, now: at(6, 9, 0), expected: false},
+		{name: "spans midnight", window: overnight, now: at(6, 5, 0), expected: true},
+		{name: "after overnight window", window: overnight, now: at(6, 6, 0), expected: false},
+		{name: "schedule that never fires", window: ScalingWindow{Schedule: "0 0 30 2 *", Duration: "24h", Replicas: 5}, now: at(1, 12, 0), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.IsActive(tt.now); got != tt.expected {
+				t.Errorf("ScalingWindow.IsActive() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScalingWindow_IsActiveIn_TimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	utc := func(month time.Month, day, hour, minute int) int64 {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC).Unix()
+	}
+	recurring := func(days, start, end string) ScalingWindow {
+		return ScalingWindow{Days: []string{days}, StartTimeOfDay: start, EndTimeOfDay: end, Replicas: 3}
+	}
+
+	// New York springs forward on 2024-03-10 at 02:00 EST (07:00 UTC)
+	// and falls back on 2024-11-03 at 02:00 EDT (06:00 UTC)
+	tests := []struct {
+		name     string
+		window   ScalingWindow
+		now      int64
+		expected bool
+	}{
+		{name: "winter business hours", window: recurring("Mon-Fri", "08:00", "18:00"), now: utc(time.January, 8, 13, 30), expected: true},
+		{name: "winter before business hours", window: recurring("Mon-Fri", "08:00", "18:00"), now: utc(time.January, 8, 12, 30), expected: false},
+		{name: "summer business hours", window: recurring("Mon-Fri", "08:00", "18:00"), now: utc(time.July, 8, 12, 30), expected: true},
+		{name: "summer after business hours", window: recurring("Mon-Fri", "08:00", "18:00"), now: utc(time.July, 8, 22, 0), expected: false},
+		{name: "local weekday differs from UTC weekday", window: recurring("Fri", "20:00", "23:00"), now: utc(time.January, 13, 2, 0), expected: true},
+
+		{name: "spring forward before gap", window: recurring("Sun", "01:00", "04:00"), now: utc(time.March, 10, 6, 30), expected: true},
+		{name: "spring forward after gap", window: recurring("Sun", "01:00", "04:00"), now: utc(time.March, 10, 7, 30), expected: true},
+		{name: "spring forward end", window: recurring("Sun", "01:00", "04:00"), now: utc(time.March, 10, 8, 0), expected: false},
+		{name: "window starting in gap opens at transition", window: recurring("Sun", "02:30", "05:00"), now: utc(time.March, 10, 7, 0), expected: true},
+		{name: "window starting in gap not open before transition", window: recurring("Sun", "02:30", "05:00"), now: utc(time.March, 10, 6, 59), expected: false},
+		{name: "window inside gap is not skipped", window: recurring("Sun", "02:00", "02:30"), now: utc(time.March, 10, 7, 15), expected: true},
+		{name: "window inside gap keeps its length", window: recurring("Sun", "02:00", "02:30"), now: utc(time.March, 10, 7, 30), expected: false},
+
+		{name: "fall back first occurrence", window: recurring("Sun", "01:00", "01:30"), now: utc(time.November, 3, 5, 15), expected: true},
+		{name: "fall back second occurrence is not doubled", window: recurring("Sun", "01:00", "01:30"), now: utc(time.November, 3, 6, 15), expected: false},
+		{name: "overnight across fall back", window: recurring("Sat", "22:00", "06:00"), now: utc(time.November, 3, 10, 59), expected: true},
+		{name: "overnight across fall back ends on local time", window: recurring("Sat", "22:00", "06:00"), now: utc(time.November, 3, 11, 0), expected: false},
+
+		{name: "cron in local time", window: ScalingWindow{Schedule: "0 8 * * 1-5", Duration: "1h", Replicas: 3}, now: utc(time.July, 8, 12, 30), expected: true},
+		{name: "cron not at UTC time", window: ScalingWindow{Schedule: "0 8 * * 1-5", Duration: "1h", Replicas: 3}, now: utc(time.July, 8, 8, 30), expected: false},
+		{name: "cron across spring forward in local time", window: ScalingWindow{Schedule: "0 8 * * *", Duration: "1h", Replicas: 3}, now: utc(time.March, 10, 12, 30), expected: true},
+		{name: "cron starting in gap opens at transition", window: ScalingWindow{Schedule: "30 2 * * *", Duration: "1h", Replicas: 3}, now: utc(time.March, 10, 7, 30), expected: true},
+		{name: "cron starting in gap not open before transition", window: ScalingWindow{Schedule: "30 2 * * *", Duration: "1h", Replicas: 3}, now: utc(time.March, 10, 6, 59), expected: false},
+		{name: "cron starting in gap keeps its duration", window: ScalingWindow{Schedule: "30 2 * * *", Duration: "1h", Replicas: 3}, now: utc(time.March, 10, 8, 0), expected: false},
+		{name: "cron fall back first occurrence", window: ScalingWindow{Schedule: "30 1 * * *", Duration: "20m", Replicas: 3}, now: utc(time.November, 3, 5, 40), expected: true},
+		{name: "cron fall back second occurrence is not doubled", window: ScalingWindow{Schedule: "30 1 * * *", Duration: "20m", Replicas: 3}, now: utc(time.November, 3, 6, 40), expected: false},
+		{name: "cron spanning fall back runs for its duration", window: ScalingWindow{Schedule: "0 1 * * *", Duration: "90m", Replicas: 3}, now: utc(time.November, 3, 6, 15), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.IsActiveIn(tt.now, newYork); got != tt.expected {
+				t.Errorf("ScalingWindow.IsActiveIn() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestScalingWindow_Validate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -110,6 +256,173 @@ func TestScalingWindow_Validate(t *testing.T) {
 			wantErr:     true,
 			errContains: "replicas cannot be negative",
 		},
+		{
+			name: "valid recurring window",
+			window: ScalingWindow{
+				Days:           []string{"Mon-Fri"},
+				StartTimeOfDay: "08:00",
+				EndTimeOfDay:   "18:00",
+				Replicas:       3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "recurring window without days",
+			window: ScalingWindow{
+				StartTimeOfDay: "08:00",
+				EndTimeOfDay:   "18:00",
+				Replicas:       3,
+			},
+			wantErr:     true,
+			errContains: "at least one day",
+		},
+		{
+			name: "recurring window with invalid day",
+			window: ScalingWindow{
+				Days:           []string{"Funday"},
+				StartTimeOfDay: "08:00",
+				EndTimeOfDay:   "18:00",
+				Replicas:       3,
+			},
+			wantErr:     true,
+			errContains: "invalid day of week",
+		},
+		{
+			name: "recurring window with invalid time of day",
+			window: ScalingWindow{
+				Days:           []string{"Mon"},
+				StartTimeOfDay: "8am",
+				EndTimeOfDay:   "18:00",
+				Replicas:       3,
+			},
+			wantErr:     true,
+			errContains: "invalid start time of day",
+		},
+		{
+			name: "recurring window with equal times",
+			window: ScalingWindow{
+				Days:           []string{"Mon"},
+				StartTimeOfDay: "08:00",
+				EndTimeOfDay:   "08:00",
+				Replicas:       3,
+			},
+			wantErr:     true,
+			errContains: "must differ",
+		},
+		{
+			name: "recurring window mixed with absolute times",
+			window: ScalingWindow{
+				StartTime:      100,
+				Days:           []string{"Mon"},
+				StartTimeOfDay: "08:00",
+				EndTimeOfDay:   "18:00",
+				Replicas:       3,
+			},
+			wantErr:     true,
+			errContains: "cannot also set startTime",
+		},
+		{
+			name: "valid cron window",
+			window: ScalingWindow{
+				Schedule: "0 8 * * 1-5",
+				Duration: "10h",
+				Replicas: 3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "malformed cron schedule",
+			window: ScalingWindow{
+				Schedule: "0 25 * * *",
+				Duration: "1h",
+				Replicas: 3,
+			},
+			wantErr:     true,
+			errContains: `invalid cron schedule "0 25 * * *"`,
+		},
+		{
+			name: "cron schedule that never fires",
+			window: ScalingWindow{
+				Schedule: "0 0 30 2 *",
+				Duration: "1h",
+				Replicas: 3,
+			},
+			wantErr:     true,
+			errContains: "never fires",
+		},
+		{
+			name: "cron window without duration",
+			window: ScalingWindow{
+				Schedule: "0 8 * * *",
+				Replicas: 3,
+			},
+			wantErr:     true,
+			errContains: "require a duration",
+		},
+		{
+			name: "cron window with invalid duration",
+			window: ScalingWindow{
+				Schedule: "0 8 * * *",
+				Duration: "ten hours",
+				Replicas: 3,
+			},
+			wantErr:     true,
+			errContains: "invalid duration",
+		},
+		{
+			name: "duration without schedule",
+			window: ScalingWindow{
+				StartTime: 100,
+				EndTime:   200,
+				Duration:  "1h",
+				Replicas:  3,
+			},
+			wantErr:     true,
+			errContains: "duration requires a cron schedule",
+		},
+		{
+			name: "valid ramp",
+			window: ScalingWindow{
+				StartTime: 100,
+				EndTime:   200,
+				Replicas:  10,
+				Ramp:      &RampPolicy{StepSize: 2, StepInterval: "1m", Duration: "10m"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ramp without step size or duration",
+			window: ScalingWindow{
+				StartTime: 100,
+				EndTime:   200,
+				Replicas:  10,
+				Ramp:      &RampPolicy{StepInterval: "1m"},
+			},
+			wantErr:     true,
+			errContains: "ramp requires a step size or a duration",
+		},
+		{
+			name: "ramp with negative step size",
+			window: ScalingWindow{
+				StartTime: 100,
+				EndTime:   200,
+				Replicas:  10,
+				Ramp:      &RampPolicy{StepSize: -1},
+			},
+			wantErr:     true,
+			errContains: "step size cannot be negative",
+		},
+		{
+			name: "ramp with invalid step interval",
+			window: ScalingWindow{
+				StartTime: 100,
+				EndTime:   200,
+				Replicas:  10,
+				Ramp:      &RampPolicy{StepSize: 1, StepInterval: "soon"},
+			},
+			wantErr:     true,
+			errContains: "invalid ramp step interval",
+		},
 	}
 
 	for _, tt := range tests {
@@ -189,6 +502,90 @@ func TestResource_GetDesiredReplicas(t *testing.T) {
 			now:  now,
 			want: 5,
 		},
+		{
+			name: "active recurring window returns window replicas",
+			resource: Resource{
+				Name:             "test-resource",
+				OriginalReplicas: 2,
+				Windows: []ScalingWindow{
+					{
+						Days:           []string{"Mon-Fri"},
+						StartTimeOfDay: "08:00",
+						EndTimeOfDay:   "18:00",
+						Replicas:       4,
+					},
+				},
+			},
+			now:  time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC).Unix(),
+			want: 4,
+		},
+		{
+			name: "recurring window evaluated in resource time zone",
+			resource: Resource{
+				Name:             "test-resource",
+				OriginalReplicas: 2,
+				TimeZone:         "Asia/Tokyo",
+				Windows: []ScalingWindow{
+					{
+						Days:           []string{"Mon-Fri"},
+						StartTimeOfDay: "08:00",
+						EndTimeOfDay:   "18:00",
+						Replicas:       4,
+					},
+				},
+			},
+			// 10:00 UTC is 19:00 in Tokyo
+			now:  time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC).Unix(),
+			want: 2,
+		},
+		{
+			name: "calendar day suppresses active window",
+			resource: Resource{
+				Name:             "test-resource",
+				OriginalReplicas: 2,
+				Calendar:         "holidays",
+				Holidays:         &Calendar{Name: "holidays", Days: []CalendarDay{{Date: time.Unix(now, 0).UTC().Format("2006-01-02")}}},
+				Windows: []ScalingWindow{
+					{
+						StartTime: now - 50,
+						EndTime:   now + 50,
+						Replicas:  5,
+					},
+				},
+			},
+			now:  now,
+			want: 2,
+		},
+		{
+			name: "calendar day uses holiday replicas",
+			resource: Resource{
+				Name:             "test-resource",
+				OriginalReplicas: 2,
+				Calendar:         "holidays",
+				HolidayReplicas:  int32Ptr(0),
+				Holidays:         &Calendar{Name: "holidays", Days: []CalendarDay{{Date: time.Unix(now, 0).UTC().Format("2006-01-02")}}},
+			},
+			now:  now,
+			want: 0,
+		},
+		{
+			name: "unresolved calendar is ignored",
+			resource: Resource{
+				Name:             "test-resource",
+				OriginalReplicas: 2,
+				Calendar:         "holidays",
+				HolidayReplicas:  int32Ptr(0),
+				Windows: []ScalingWindow{
+					{
+						StartTime: now - 50,
+						EndTime:   now + 50,
+						Replicas:  5,
+					},
+				},
+			},
+			now:  now,
+			want: 5,
+		},
 	}
 
 	for _, tt := range tests {
@@ -200,6 +597,37 @@ func TestResource_GetDesiredReplicas(t *testing.T) {
 	}
 }
 
+func TestResource_Location(t *testing.T) {
+	resource := Resource{
+		Name:             "test-resource",
+		Namespace:        "default",
+		Target:           Target{Name: "test", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		TimeZone:         "America/New_York",
+	}
+	if err := resource.Validate(); err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	loc := resource.Location()
+	if loc.String() != "America/New_York" {
+		t.Fatalf("Resource.Location() = %v, want America/New_York", loc)
+	}
+	if resource.Location() != loc {
+		t.Error("Resource.Location() did not reuse the zone resolved by Validate")
+	}
+
+	resource.TimeZone = "Europe/Berlin"
+	if got := resource.Location(); got.String() != "Europe/Berlin" {
+		t.Errorf("Resource.Location() after changing the time zone = %v, want Europe/Berlin", got)
+	}
+
+	resource.TimeZone = ""
+	if got := resource.Location(); got != time.UTC {
+		t.Errorf("Resource.Location() without a time zone = %v, want UTC", got)
+	}
+}
+
 func TestResource_Validate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -314,6 +742,239 @@ func TestResource_Validate(t *testing.T) {
 			wantErr:     true,
 			errContains: "window 0 is invalid",
 		},
+		{
+			name: "malformed cron window names resource and index",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				Windows: []ScalingWindow{
+					{
+						StartTime: 100,
+						EndTime:   200,
+						Replicas:  3,
+					},
+					{
+						Schedule: "not a cron",
+						Duration: "1h",
+						Replicas: 3,
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "resource default/test-resource: window 1 is invalid: invalid cron schedule",
+		},
+		{
+			name: "invalid time zone",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				TimeZone:         "Mars/Olympus_Mons",
+			},
+			wantErr:     true,
+			errContains: "invalid time zone",
+		},
+		{
+			name: "holiday replicas without calendar",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				HolidayReplicas:  int32Ptr(1),
+			},
+			wantErr:     true,
+			errContains: "holiday replicas require a calendar",
+		},
+		{
+			name: "unknown overlap policy",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				OverlapPolicy:    "random",
+			},
+			wantErr:     true,
+			errContains: `unknown overlap policy "random"`,
+		},
+		{
+			name: "custom target kind without apiVersion",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "rollout-1",
+					Kind: "Rollout",
+				},
+				OriginalReplicas: 2,
+			},
+			wantErr:     true,
+			errContains: "target apiVersion is required for kind Rollout",
+		},
+		{
+			name: "valid hpa mode",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				ScaleMode:        ScaleModeHPA,
+				Windows: []ScalingWindow{
+					{StartTime: 100, EndTime: 200, Replicas: 5, MaxReplicas: int32Ptr(20)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown scale mode",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				ScaleMode:        "vpa",
+			},
+			wantErr:     true,
+			errContains: `unknown scale mode "vpa"`,
+		},
+		{
+			name: "max replicas outside hpa mode",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				Windows: []ScalingWindow{
+					{StartTime: 100, EndTime: 200, Replicas: 5, MaxReplicas: int32Ptr(20)},
+				},
+			},
+			wantErr:     true,
+			errContains: `max replicas requires scale mode "hpa"`,
+		},
+		{
+			name: "max replicas below replicas",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				ScaleMode:        ScaleModeHPA,
+				Windows: []ScalingWindow{
+					{StartTime: 100, EndTime: 200, Replicas: 5, MaxReplicas: int32Ptr(3)},
+				},
+			},
+			wantErr:     true,
+			errContains: "max replicas cannot be less than replicas",
+		},
+		{
+			name: "zero replicas in hpa mode",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				ScaleMode:        ScaleModeHPA,
+				Windows: []ScalingWindow{
+					{StartTime: 100, EndTime: 200, Replicas: 0},
+				},
+			},
+			wantErr:     true,
+			errContains: "replicas must be at least 1",
+		},
+		{
+			name: "capture baseline in hpa mode",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "deployment-1",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				ScaleMode:        ScaleModeHPA,
+				CaptureBaseline:  true,
+			},
+			wantErr:     true,
+			errContains: "capture baseline is not supported",
+		},
+		{
+			name: "valid cronjob target",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "nightly-backup",
+					Kind: "CronJob",
+				},
+				OriginalReplicas: CronJobActive,
+				Windows: []ScalingWindow{
+					{StartTime: 100, EndTime: 200, Replicas: CronJobSuspended},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cronjob window with replica count",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "nightly-backup",
+					Kind: "CronJob",
+				},
+				OriginalReplicas: CronJobActive,
+				Windows: []ScalingWindow{
+					{StartTime: 100, EndTime: 200, Replicas: 3},
+				},
+			},
+			wantErr:     true,
+			errContains: "replicas must be 0 (suspended) or 1 (active)",
+		},
+		{
+			name: "cronjob original replicas",
+			resource: Resource{
+				Name:      "test-resource",
+				Namespace: "default",
+				Target: Target{
+					Name: "nightly-backup",
+					Kind: "CronJob",
+				},
+				OriginalReplicas: 2,
+			},
+			wantErr:     true,
+			errContains: "original replicas must be 0 (suspended) or 1 (active)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -332,6 +993,10 @@ func TestResource_Validate(t *testing.T) {
 	}
 }
 
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
 // Helper function to check if a string contains another string
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
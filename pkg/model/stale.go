@@ -0,0 +1,46 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stale policies decide what happens to resources whose configuration could not be
+// refreshed for longer than its provider's maximum staleness
+const (
+	// StalePolicyKeep keeps applying the stale schedule (the default)
+	StalePolicyKeep = "keep"
+	// StalePolicyFreeze stops scaling the resource, leaving its target as it is
+	StalePolicyFreeze = "freeze"
+	// StalePolicyRevert returns the target to OriginalReplicas, ignoring windows
+	StalePolicyRevert = "revert"
+)
+
+// Staleness marks a resource served from configuration older than its provider's maximum staleness
+type Staleness struct {
+	// Policy is the stale policy to apply
+	Policy string
+	// FetchedAt is when the configuration was last fetched successfully
+	FetchedAt time.Time
+}
+
+// ValidateStalePolicy checks that policy is a known stale policy or empty
+func ValidateStalePolicy(policy string) error {
+	switch policy {
+	case "", StalePolicyKeep, StalePolicyFreeze, StalePolicyRevert:
+		return nil
+	default:
+		return fmt.Errorf("unknown stale policy %q, expected %q, %q or %q",
+			policy, StalePolicyKeep, StalePolicyFreeze, StalePolicyRevert)
+	}
+}
+
+// Reverted returns a copy of the resource that schedules OriginalReplicas at all times
+func (r *Resource) Reverted() Resource {
+	reverted := *r
+	reverted.Windows = nil
+	reverted.Calendar = ""
+	reverted.Holidays = nil
+	reverted.HolidayReplicas = nil
+	return reverted
+}
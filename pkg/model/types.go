@@ -49,7 +49,14 @@ func (in *ScheduledResource) DeepCopyObject() runtime.Object {
 type ScheduledResourceSpec struct {
 	Target           ResourceTarget `json:"target"`
 	OriginalReplicas int32          `json:"originalReplicas"`
+	TimeZone         string         `json:"timeZone,omitempty"`
 	Windows          []Window       `json:"windows"`
+	Calendar         string         `json:"calendar,omitempty"`
+	HolidayReplicas  *int32         `json:"holidayReplicas,omitempty"`
+	OverlapPolicy    string         `json:"overlapPolicy,omitempty"`
+	CaptureBaseline  bool           `json:"captureBaseline,omitempty"`
+	ScaleMode        string         `json:"scaleMode,omitempty"`
+	HPAName          string         `json:"hpaName,omitempty"`
 }
 
 func (in *ScheduledResourceSpec) DeepCopyInto(out *ScheduledResourceSpec) {
@@ -58,7 +65,14 @@ func (in *ScheduledResourceSpec) DeepCopyInto(out *ScheduledResourceSpec) {
 	if in.Windows != nil {
 		in, out := &in.Windows, &out.Windows
 		*out = make([]Window, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HolidayReplicas != nil {
+		in, out := &in.HolidayReplicas, &out.HolidayReplicas
+		*out = new(int32)
+		**out = **in
 	}
 }
 
@@ -69,9 +83,36 @@ type ResourceTarget struct {
 }
 
 type Window struct {
-	StartTime int64 `json:"startTime"`
-	EndTime   int64 `json:"endTime"`
-	Replicas  int32 `json:"replicas"`
+	StartTime      int64       `json:"startTime,omitempty"`
+	EndTime        int64       `json:"endTime,omitempty"`
+	Days           []string    `json:"days,omitempty"`
+	StartTimeOfDay string      `json:"startTimeOfDay,omitempty"`
+	EndTimeOfDay   string      `json:"endTimeOfDay,omitempty"`
+	Schedule       string      `json:"schedule,omitempty"`
+	Duration       string      `json:"duration,omitempty"`
+	Replicas       int32       `json:"replicas"`
+	Priority       int32       `json:"priority,omitempty"`
+	Ramp           *RampPolicy `json:"ramp,omitempty"`
+	MaxReplicas    *int32      `json:"maxReplicas,omitempty"`
+}
+
+func (in *Window) DeepCopyInto(out *Window) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ramp != nil {
+		in, out := &in.Ramp, &out.Ramp
+		*out = new(RampPolicy)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 type ScheduledResourceList struct {
@@ -106,10 +147,85 @@ func (in *ScheduledResourceList) DeepCopyObject() runtime.Object {
 	return in.DeepCopy()
 }
 
+// HolidayCalendar is a cluster-scoped calendar that ScheduledResources reference by name
+type HolidayCalendar struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HolidayCalendarSpec `json:"spec"`
+}
+
+func (in *HolidayCalendar) DeepCopyInto(out *HolidayCalendar) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+func (in *HolidayCalendar) DeepCopy() *HolidayCalendar {
+	if in == nil {
+		return nil
+	}
+	out := new(HolidayCalendar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *HolidayCalendar) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+type HolidayCalendarSpec struct {
+	Days []CalendarDay `json:"days"`
+}
+
+func (in *HolidayCalendarSpec) DeepCopyInto(out *HolidayCalendarSpec) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]CalendarDay, len(*in))
+		copy(*out, *in)
+	}
+}
+
+type HolidayCalendarList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HolidayCalendar `json:"items"`
+}
+
+func (in *HolidayCalendarList) DeepCopyInto(out *HolidayCalendarList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HolidayCalendar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+func (in *HolidayCalendarList) DeepCopy() *HolidayCalendarList {
+	if in == nil {
+		return nil
+	}
+	out := new(HolidayCalendarList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *HolidayCalendarList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&ScheduledResource{},
 		&ScheduledResourceList{},
+		&HolidayCalendar{},
+		&HolidayCalendarList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -24,6 +27,9 @@ type ScheduledResourceReconciler struct {
 	Recorder  record.EventRecorder
 	scheduler *scheduler.Scheduler
 	provider  *config.CRDProvider
+	// warnings holds the warnings last recorded as events for each ScheduledResource
+	warnings   map[types.NamespacedName]string
+	warningsMu sync.Mutex
 }
 
 func (r *ScheduledResourceReconciler) SetupWithManager(mgr ctrl.Manager, sched *scheduler.Scheduler, provider *config.CRDProvider) error {
@@ -32,6 +38,7 @@ func (r *ScheduledResourceReconciler) SetupWithManager(mgr ctrl.Manager, sched *
 	r.Recorder = mgr.GetEventRecorderFor("k8schedul8r-controller")
 	r.scheduler = sched
 	r.provider = provider
+	r.warnings = make(map[types.NamespacedName]string)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&model.ScheduledResource{}).
@@ -50,6 +57,7 @@ func (r *ScheduledResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		if errors.IsNotFound(err) {
 			// Resource was deleted, remove from provider cache
 			r.provider.DeleteResource(req.Namespace, req.Name)
+			r.warningsChanged(req.NamespacedName, nil)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -66,7 +74,20 @@ func (r *ScheduledResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			APIVersion: scheduledResource.Spec.Target.APIVersion,
 		},
 		OriginalReplicas: scheduledResource.Spec.OriginalReplicas,
+		TimeZone:         scheduledResource.Spec.TimeZone,
 		Windows:          convertWindows(scheduledResource.Spec.Windows),
+		Calendar:         scheduledResource.Spec.Calendar,
+		HolidayReplicas:  scheduledResource.Spec.HolidayReplicas,
+		OverlapPolicy:    scheduledResource.Spec.OverlapPolicy,
+		CaptureBaseline:  scheduledResource.Spec.CaptureBaseline,
+		ScaleMode:        scheduledResource.Spec.ScaleMode,
+		HPAName:          scheduledResource.Spec.HPAName,
+		Provenance: &model.Provenance{
+			Provider: model.ProviderCRD,
+			Source:   fmt.Sprintf("ScheduledResource %s/%s", scheduledResource.Namespace, scheduledResource.Name),
+			Revision: scheduledResource.ResourceVersion,
+			LoadedAt: time.Now(),
+		},
 	}
 
 	// Validate the resource
@@ -80,29 +101,68 @@ func (r *ScheduledResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// Trigger immediate scaling check
 	now := time.Now().Unix()
-	desiredReplicas := resource.GetDesiredReplicas(now)
+	// Validate found the overlapping windows; record them once each time they change
+	// rather than on every requeue
+	if r.warningsChanged(req.NamespacedName, resource.Warnings) {
+		for _, warning := range resource.Warnings {
+			r.Recorder.Event(&scheduledResource, "Warning", "OverlappingWindows", warning)
+		}
+	}
 
-	if err := r.scheduler.ScaleResource(ctx, &resource, desiredReplicas); err != nil {
+	replicas, err := r.scheduler.Apply(ctx, &resource, now)
+	if err != nil {
 		r.Recorder.Event(&scheduledResource, "Warning", "ScalingFailed",
 			fmt.Sprintf("Failed to scale resource: %v", err))
 		return ctrl.Result{}, err
 	}
 
 	r.Recorder.Event(&scheduledResource, "Normal", "Scaled",
-		fmt.Sprintf("Successfully scaled %s %s/%s to %d replicas",
-			resource.Target.Kind, resource.Namespace, resource.Target.Name, desiredReplicas))
+		fmt.Sprintf("Successfully scaled %s %s/%s to %d replicas (from %s)",
+			resource.Target.Kind, resource.Namespace, resource.Target.Name, replicas, resource.Provenance))
 
 	// Requeue after a minute to ensure we keep checking the schedule
 	return ctrl.Result{RequeueAfter: time.Minute}, nil
 }
 
+// warningsChanged records warnings as the last ones seen for the ScheduledResource
+// and reports whether they differ from those seen before
+func (r *ScheduledResourceReconciler) warningsChanged(name types.NamespacedName, warnings []string) bool {
+	r.warningsMu.Lock()
+	defer r.warningsMu.Unlock()
+
+	joined := strings.Join(warnings, "\n")
+	if r.warnings[name] == joined {
+		return false
+	}
+	if joined == "" {
+		delete(r.warnings, name)
+	} else {
+		r.warnings[name] = joined
+	}
+	return true
+}
+
 func convertWindows(windows []model.Window) []model.ScalingWindow {
 	result := make([]model.ScalingWindow, len(windows))
 	for i, w := range windows {
 		result[i] = model.ScalingWindow{
-			StartTime: w.StartTime,
-			EndTime:   w.EndTime,
-			Replicas:  w.Replicas,
+			StartTime:      w.StartTime,
+			EndTime:        w.EndTime,
+			Days:           append([]string(nil), w.Days...),
+			StartTimeOfDay: w.StartTimeOfDay,
+			EndTimeOfDay:   w.EndTimeOfDay,
+			Schedule:       w.Schedule,
+			Duration:       w.Duration,
+			Replicas:       w.Replicas,
+			Priority:       w.Priority,
+		}
+		if w.Ramp != nil {
+			ramp := *w.Ramp
+			result[i].Ramp = &ramp
+		}
+		if w.MaxReplicas != nil {
+			maxReplicas := *w.MaxReplicas
+			result[i].MaxReplicas = &maxReplicas
 		}
 	}
 	return result
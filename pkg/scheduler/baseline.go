@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// BaselineAnnotation records a target's live replica count from before a window started
+const BaselineAnnotation = "k8schedul8r.io/baseline-replicas"
+
+// applyWithBaseline scales a resource in capture-baseline mode. When a window starts
+// the live replica count is recorded on the target; when it ends that count is
+// restored and the record removed. Outside windows the target is left alone so
+// manual changes to the baseline are preserved.
+func (s *Scheduler) applyWithBaseline(ctx context.Context, res *model.Resource, scheduled bool, desiredReplicas int32) (int32, error) {
+	current, annotations, err := s.getTargetState(ctx, res)
+	if err != nil {
+		return 0, err
+	}
+	recorded, hasRecorded := annotations[BaselineAnnotation]
+
+	if scheduled {
+		if !hasRecorded {
+			value := strconv.Itoa(int(current))
+			if err := s.patchAnnotation(ctx, res, &value); err != nil {
+				return 0, fmt.Errorf("failed to record baseline replicas: %w", err)
+			}
+			s.logger.Printf("Recorded baseline of %d replicas for %s %s/%s",
+				current, res.Target.Kind, res.Namespace, res.Target.Name)
+		}
+		if err := s.ScaleResource(ctx, res, desiredReplicas); err != nil {
+			return 0, err
+		}
+		return desiredReplicas, nil
+	}
+
+	if !hasRecorded {
+		// No window has run since the last restore, keep the live count
+		return current, nil
+	}
+
+	baseline, err := strconv.ParseInt(recorded, 10, 32)
+	if err != nil || baseline < 0 {
+		return 0, fmt.Errorf("invalid %s annotation %q on %s %s/%s",
+			BaselineAnnotation, recorded, res.Target.Kind, res.Namespace, res.Target.Name)
+	}
+	if err := s.ScaleResource(ctx, res, int32(baseline)); err != nil {
+		return 0, err
+	}
+	// Only clear the record once the restore succeeded, so a failure is retried
+	if err := s.patchAnnotation(ctx, res, nil); err != nil {
+		return 0, fmt.Errorf("failed to clear baseline replicas: %w", err)
+	}
+	s.logger.Printf("Restored baseline of %d replicas for %s %s/%s",
+		baseline, res.Target.Kind, res.Namespace, res.Target.Name)
+	return int32(baseline), nil
+}
+
+// getTargetState returns the target's current replica count and annotations
+func (s *Scheduler) getTargetState(ctx context.Context, res *model.Resource) (int32, map[string]string, error) {
+	if s.dynamic == nil {
+		return 0, nil, fmt.Errorf("no dynamic client configured")
+	}
+
+	current, err := s.currentReplicas(ctx, res)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	mapping, err := s.targetMapping(res)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	obj, err := s.dynamic.Resource(mapping.Resource).Namespace(res.Namespace).Get(ctx, res.Target.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get %s: %w", strings.ToLower(res.Target.Kind), err)
+	}
+
+	return current, obj.GetAnnotations(), nil
+}
+
+// patchAnnotation sets the baseline annotation on the target, or removes it if value is nil
+func (s *Scheduler) patchAnnotation(ctx context.Context, res *model.Resource, value *string) error {
+	mapping, err := s.targetMapping(res)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]*string{BaselineAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dynamic.Resource(mapping.Resource).Namespace(res.Namespace).
+		Patch(ctx, res.Target.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
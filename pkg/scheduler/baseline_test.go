@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestScheduler_Apply_CaptureBaseline(t *testing.T) {
+	now := time.Now().Unix()
+	res := model.Resource{
+		Name:      "test-scaler",
+		Namespace: "default",
+		Target: model.Target{
+			Name: "test-deployment",
+			Kind: "Deployment",
+		},
+		OriginalReplicas: 2,
+		CaptureBaseline:  true,
+		Windows: []model.ScalingWindow{
+			{
+				StartTime: now,
+				EndTime:   now + 3600,
+				Replicas:  5,
+			},
+		},
+	}
+
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 3))
+	s, err := New(&mockProvider{}, Options{
+		PollInterval:  time.Second,
+		Logger:        newTestLogger(),
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	ctx := context.Background()
+
+	deployments := appsv1.SchemeGroupVersion.WithResource("deployments")
+	check := func(step string, wantReplicas int64, wantAnnotation string) {
+		t.Helper()
+		replicas, annotations := getReplicas(t, dynamicClient, deployments, "default", "test-deployment")
+		if replicas != wantReplicas {
+			t.Errorf("%s: replicas = %d, want %d", step, replicas, wantReplicas)
+		}
+		if got := annotations[BaselineAnnotation]; got != wantAnnotation {
+			t.Errorf("%s: baseline annotation = %q, want %q", step, got, wantAnnotation)
+		}
+	}
+
+	// Before the window the live count is left alone rather than set to OriginalReplicas
+	if _, err := s.Apply(ctx, &res, now-60); err != nil {
+		t.Fatalf("Apply() before window failed: %v", err)
+	}
+	check("before window", 3, "")
+
+	// When the window starts the live count is recorded
+	if _, err := s.Apply(ctx, &res, now+60); err != nil {
+		t.Fatalf("Apply() in window failed: %v", err)
+	}
+	check("in window", 5, "3")
+
+	// Later ticks in the window keep the first recorded value
+	if _, err := s.Apply(ctx, &res, now+120); err != nil {
+		t.Fatalf("Apply() later in window failed: %v", err)
+	}
+	check("later in window", 5, "3")
+
+	// When the window ends the recorded count is restored and the record removed
+	replicas, err := s.Apply(ctx, &res, now+3600)
+	if err != nil {
+		t.Fatalf("Apply() after window failed: %v", err)
+	}
+	if replicas != 3 {
+		t.Errorf("Apply() after window = %d, want 3", replicas)
+	}
+	check("after window", 3, "")
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// suspendCronJob suspends the CronJob target for a replica count of 0 and resumes it otherwise
+func (s *Scheduler) suspendCronJob(ctx context.Context, res *model.Resource, replicas int32) error {
+	cronJobs := s.client.BatchV1().CronJobs(res.Namespace)
+
+	cronJob, err := cronJobs.Get(ctx, res.Target.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get cronjob: %w", err)
+	}
+
+	suspend := replicas == model.CronJobSuspended
+	state := "active"
+	if suspend {
+		state = "suspended"
+	}
+
+	if suspended := cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend; suspended == suspend {
+		s.logger.Printf("CronJob %s/%s already %s", res.Namespace, res.Target.Name, state)
+		return nil
+	}
+
+	cronJob.Spec.Suspend = &suspend
+	if _, err := cronJobs.Update(ctx, cronJob, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update cronjob: %w", err)
+	}
+
+	s.logger.Printf("Successfully set cronjob %s/%s %s", res.Namespace, res.Target.Name, state)
+	return nil
+}
+
+// cronJobReplicas reports a CronJob target's state as a replica count, 0 when suspended and 1 when active
+func (s *Scheduler) cronJobReplicas(ctx context.Context, res *model.Resource) (int32, error) {
+	cronJob, err := s.client.BatchV1().CronJobs(res.Namespace).Get(ctx, res.Target.Name, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cronjob: %w", err)
+	}
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		return model.CronJobSuspended, nil
+	}
+	return model.CronJobActive, nil
+}
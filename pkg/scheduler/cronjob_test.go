@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func createTestCronJob(name, namespace string, suspend *bool) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 2 * * *",
+			Suspend:  suspend,
+		},
+	}
+}
+
+func TestScheduler_Apply_CronJob(t *testing.T) {
+	now := time.Now().Unix()
+	res := model.Resource{
+		Name:      "nightly-pause",
+		Namespace: "default",
+		Target: model.Target{
+			Name: "nightly-backup",
+			Kind: "CronJob",
+		},
+		OriginalReplicas: model.CronJobActive,
+		Windows: []model.ScalingWindow{
+			{
+				StartTime: now,
+				EndTime:   now + 3600,
+				Replicas:  model.CronJobSuspended,
+			},
+		},
+	}
+	if err := res.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+
+	client := fake.NewSimpleClientset(createTestCronJob("nightly-backup", "default", nil))
+	s, err := New(&mockProvider{}, Options{
+		PollInterval: time.Second,
+		Logger:       newTestLogger(),
+		Client:       client,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		now         int64
+		wantSuspend bool
+	}{
+		{name: "before window", now: now - 60, wantSuspend: false},
+		{name: "in window", now: now + 60, wantSuspend: true},
+		{name: "later in window", now: now + 120, wantSuspend: true},
+		{name: "after window", now: now + 3600, wantSuspend: false},
+	}
+
+	for _, tt := range tests {
+		if _, err := s.Apply(ctx, &res, tt.now); err != nil {
+			t.Fatalf("%s: Apply() failed: %v", tt.name, err)
+		}
+		cronJob, err := client.BatchV1().CronJobs("default").Get(ctx, "nightly-backup", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("%s: failed to get cronjob: %v", tt.name, err)
+		}
+		suspended := cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+		if suspended != tt.wantSuspend {
+			t.Errorf("%s: suspended = %v, want %v", tt.name, suspended, tt.wantSuspend)
+		}
+	}
+
+	replicas, err := s.currentReplicas(ctx, &res)
+	if err != nil {
+		t.Fatalf("currentReplicas() failed: %v", err)
+	}
+	if replicas != model.CronJobActive {
+		t.Errorf("currentReplicas() = %d, want %d", replicas, model.CronJobActive)
+	}
+}
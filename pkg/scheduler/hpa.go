@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HPABoundsAnnotation records an autoscaler's minReplicas and maxReplicas from before a window started
+const HPABoundsAnnotation = "k8schedul8r.io/original-hpa-bounds"
+
+// hpaBounds is the value stored in HPABoundsAnnotation
+type hpaBounds struct {
+	MinReplicas int32 `json:"minReplicas"`
+	MaxReplicas int32 `json:"maxReplicas"`
+}
+
+// applyHPA applies a resource in hpa mode. While the resource is scheduled the
+// autoscaler's minReplicas is raised or lowered to the desired replicas, and its
+// maxReplicas set from the window or kept at least as high as the minimum. The
+// original bounds are recorded on the autoscaler and restored when the window ends.
+// Outside windows the autoscaler is left alone.
+func (s *Scheduler) applyHPA(ctx context.Context, res *model.Resource, now int64, desiredReplicas int32) (int32, error) {
+	hpa, err := s.findHPA(ctx, res)
+	if err != nil {
+		return 0, err
+	}
+
+	current := hpaBounds{MinReplicas: 1, MaxReplicas: hpa.Spec.MaxReplicas}
+	if hpa.Spec.MinReplicas != nil {
+		current.MinReplicas = *hpa.Spec.MinReplicas
+	}
+
+	var original *hpaBounds
+	if recorded, ok := hpa.Annotations[HPABoundsAnnotation]; ok {
+		original = &hpaBounds{}
+		if err := json.Unmarshal([]byte(recorded), original); err != nil {
+			return 0, fmt.Errorf("invalid %s annotation %q on HorizontalPodAutoscaler %s/%s",
+				HPABoundsAnnotation, recorded, hpa.Namespace, hpa.Name)
+		}
+	}
+
+	var want hpaBounds
+	recordChanged := false
+	if res.IsScheduled(now) {
+		if original == nil {
+			recordChanged = true
+			original = &current
+			value, err := json.Marshal(original)
+			if err != nil {
+				return 0, err
+			}
+			if hpa.Annotations == nil {
+				hpa.Annotations = map[string]string{}
+			}
+			hpa.Annotations[HPABoundsAnnotation] = string(value)
+		}
+		want = hpaBounds{MinReplicas: desiredReplicas, MaxReplicas: original.MaxReplicas}
+		if window := res.ActiveWindow(now); window != nil && window.MaxReplicas != nil && !res.IsHoliday(now) {
+			want.MaxReplicas = *window.MaxReplicas
+		}
+		if want.MaxReplicas < want.MinReplicas {
+			want.MaxReplicas = want.MinReplicas
+		}
+	} else {
+		if original == nil {
+			// No window has run since the last restore, keep the live bounds
+			return current.MinReplicas, nil
+		}
+		want = *original
+		recordChanged = true
+		delete(hpa.Annotations, HPABoundsAnnotation)
+	}
+
+	if want == current && !recordChanged {
+		s.logger.Printf("HorizontalPodAutoscaler %s/%s already at %d-%d replicas",
+			hpa.Namespace, hpa.Name, want.MinReplicas, want.MaxReplicas)
+		return want.MinReplicas, nil
+	}
+
+	hpa.Spec.MinReplicas = &want.MinReplicas
+	hpa.Spec.MaxReplicas = want.MaxReplicas
+	// The bounds and the record change in one update, so a failure leaves both as they were
+	if _, err := s.client.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace).Update(ctx, hpa, metav1.UpdateOptions{}); err != nil {
+		return 0, fmt.Errorf("failed to update horizontalpodautoscaler: %w", err)
+	}
+
+	s.logger.Printf("Set HorizontalPodAutoscaler %s/%s bounds to %d-%d replicas",
+		hpa.Namespace, hpa.Name, want.MinReplicas, want.MaxReplicas)
+	return want.MinReplicas, nil
+}
+
+// findHPA returns the autoscaler named by the resource, or the one whose scale
+// target is the resource's target
+func (s *Scheduler) findHPA(ctx context.Context, res *model.Resource) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	hpas := s.client.AutoscalingV2().HorizontalPodAutoscalers(res.Namespace)
+
+	if res.HPAName != "" {
+		hpa, err := hpas.Get(ctx, res.HPAName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get horizontalpodautoscaler: %w", err)
+		}
+		return hpa, nil
+	}
+
+	list, err := hpas.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontalpodautoscalers: %w", err)
+	}
+
+	var found *autoscalingv2.HorizontalPodAutoscaler
+	for i := range list.Items {
+		ref := list.Items[i].Spec.ScaleTargetRef
+		if ref.Kind != res.Target.Kind || ref.Name != res.Target.Name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("several HorizontalPodAutoscalers target %s %s/%s, set hpaName",
+				res.Target.Kind, res.Namespace, res.Target.Name)
+		}
+		found = &list.Items[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no HorizontalPodAutoscaler targets %s %s/%s",
+			res.Target.Kind, res.Namespace, res.Target.Name)
+	}
+	return found, nil
+}
@@ -0,0 +1,190 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func createTestHPA(name, namespace, target string, minReplicas, maxReplicas int32) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       target,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+		},
+	}
+}
+
+func TestScheduler_Apply_HPAMode(t *testing.T) {
+	now := time.Now().Unix()
+	maxReplicas := int32(30)
+	res := model.Resource{
+		Name:      "test-scaler",
+		Namespace: "default",
+		Target: model.Target{
+			Name: "test-deployment",
+			Kind: "Deployment",
+		},
+		OriginalReplicas: 2,
+		ScaleMode:        model.ScaleModeHPA,
+		Windows: []model.ScalingWindow{
+			{
+				StartTime: now,
+				EndTime:   now + 3600,
+				Replicas:  12,
+			},
+			{
+				StartTime:   now + 3600,
+				EndTime:     now + 7200,
+				Replicas:    5,
+				MaxReplicas: &maxReplicas,
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset(
+		createTestHPA("other-hpa", "default", "other-deployment", 1, 3),
+		createTestHPA("test-hpa", "default", "test-deployment", 2, 10),
+	)
+	s, err := New(&mockProvider{}, Options{
+		PollInterval: time.Second,
+		Logger:       newTestLogger(),
+		Client:       client,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	ctx := context.Background()
+
+	check := func(step string, wantMin, wantMax int32, wantRecorded bool) {
+		t.Helper()
+		hpa, err := client.AutoscalingV2().HorizontalPodAutoscalers("default").Get(ctx, "test-hpa", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("%s: failed to get hpa: %v", step, err)
+		}
+		if *hpa.Spec.MinReplicas != wantMin || hpa.Spec.MaxReplicas != wantMax {
+			t.Errorf("%s: bounds = %d-%d, want %d-%d", step, *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas, wantMin, wantMax)
+		}
+		if _, recorded := hpa.Annotations[HPABoundsAnnotation]; recorded != wantRecorded {
+			t.Errorf("%s: bounds recorded = %v, want %v", step, recorded, wantRecorded)
+		}
+	}
+
+	// Before any window the autoscaler is left alone
+	if _, err := s.Apply(ctx, &res, now-60); err != nil {
+		t.Fatalf("Apply() before window failed: %v", err)
+	}
+	check("before window", 2, 10, false)
+
+	// A window above the original maximum raises both bounds
+	if _, err := s.Apply(ctx, &res, now+60); err != nil {
+		t.Fatalf("Apply() in first window failed: %v", err)
+	}
+	check("first window", 12, 12, true)
+
+	// A window with maxReplicas sets the maximum explicitly
+	if _, err := s.Apply(ctx, &res, now+3660); err != nil {
+		t.Fatalf("Apply() in second window failed: %v", err)
+	}
+	check("second window", 5, 30, true)
+
+	// Afterwards the original bounds come back
+	replicas, err := s.Apply(ctx, &res, now+7200)
+	if err != nil {
+		t.Fatalf("Apply() after windows failed: %v", err)
+	}
+	if replicas != 2 {
+		t.Errorf("Apply() after windows = %d, want 2", replicas)
+	}
+	check("after windows", 2, 10, false)
+
+	// The other autoscaler was never touched
+	other, err := client.AutoscalingV2().HorizontalPodAutoscalers("default").Get(ctx, "other-hpa", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get other hpa: %v", err)
+	}
+	if *other.Spec.MinReplicas != 1 || other.Spec.MaxReplicas != 3 {
+		t.Errorf("other hpa bounds = %d-%d, want 1-3", *other.Spec.MinReplicas, other.Spec.MaxReplicas)
+	}
+}
+
+func TestScheduler_Apply_HPAModeNotFound(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		name        string
+		hpaName     string
+		objects     []*autoscalingv2.HorizontalPodAutoscaler
+		errContains string
+	}{
+		{
+			name:        "no autoscaler targets the deployment",
+			objects:     []*autoscalingv2.HorizontalPodAutoscaler{createTestHPA("other-hpa", "default", "other-deployment", 1, 3)},
+			errContains: "no HorizontalPodAutoscaler targets",
+		},
+		{
+			name: "several autoscalers target the deployment",
+			objects: []*autoscalingv2.HorizontalPodAutoscaler{
+				createTestHPA("hpa-a", "default", "test-deployment", 1, 3),
+				createTestHPA("hpa-b", "default", "test-deployment", 1, 3),
+			},
+			errContains: "set hpaName",
+		},
+		{
+			name:        "named autoscaler missing",
+			hpaName:     "missing-hpa",
+			errContains: "failed to get horizontalpodautoscaler",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, hpa := range tt.objects {
+				if err := client.Tracker().Add(hpa); err != nil {
+					t.Fatalf("failed to add hpa: %v", err)
+				}
+			}
+			s, err := New(&mockProvider{}, Options{
+				PollInterval: time.Second,
+				Logger:       newTestLogger(),
+				Client:       client,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create scheduler: %v", err)
+			}
+
+			res := model.Resource{
+				Name:      "test-scaler",
+				Namespace: "default",
+				Target: model.Target{
+					Name: "test-deployment",
+					Kind: "Deployment",
+				},
+				ScaleMode: model.ScaleModeHPA,
+				HPAName:   tt.hpaName,
+				Windows: []model.ScalingWindow{
+					{StartTime: now, EndTime: now + 3600, Replicas: 5},
+				},
+			}
+			_, err = s.Apply(context.Background(), &res, now+60)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Apply() error = %v, should contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// rampState tracks a ramp in progress for one target
+type rampState struct {
+	from     int32
+	target   int32
+	started  time.Time
+	lastStep time.Time
+}
+
+// rampKey identifies a ramp by its target
+func rampKey(res *model.Resource) string {
+	return fmt.Sprintf("%s/%s/%s", res.Namespace, res.Target.Kind, res.Target.Name)
+}
+
+// rampReplicas returns the replica count for the next step of the window's ramp
+// towards target. Ramp progress is kept in memory, so after a restart a ramp
+// continues from the target's live replica count. Without a step interval a ramp
+// steps once per poll interval, however many checks configuration changes trigger.
+func (s *Scheduler) rampReplicas(ctx context.Context, res *model.Resource, ramp *model.RampPolicy, target int32, now time.Time) (int32, error) {
+	stepInterval, duration, err := ramp.Parse()
+	if err != nil {
+		return 0, err
+	}
+	if stepInterval == 0 {
+		// Allow for checks running a little early or late against the ticker
+		stepInterval = s.pollInterval - s.pollInterval/10
+	}
+
+	current, err := s.currentReplicas(ctx, res)
+	if err != nil {
+		return 0, err
+	}
+
+	key := rampKey(res)
+	s.rampsMu.Lock()
+	defer s.rampsMu.Unlock()
+
+	if current == target {
+		delete(s.ramps, key)
+		return target, nil
+	}
+
+	state, ok := s.ramps[key]
+	if !ok || state.target != target {
+		state = &rampState{from: current, target: target, started: now}
+		s.ramps[key] = state
+	} else if now.Sub(state.lastStep) < stepInterval {
+		return current, nil
+	}
+
+	next := nextRampStep(current, state, ramp.StepSize, duration, now)
+	if next == current {
+		return current, nil
+	}
+	state.lastStep = now
+
+	message := fmt.Sprintf("Ramping %s %s/%s from %d to %d replicas (target %d)",
+		res.Target.Kind, res.Namespace, res.Target.Name, current, next, target)
+	s.logger.Printf("%s", message)
+	s.targetEvent(res, corev1.EventTypeNormal, "Ramping", message)
+
+	if next == target {
+		delete(s.ramps, key)
+	}
+	return next, nil
+}
+
+// clearRamp forgets any ramp in progress for the resource's target
+func (s *Scheduler) clearRamp(res *model.Resource) {
+	s.rampsMu.Lock()
+	delete(s.ramps, rampKey(res))
+	s.rampsMu.Unlock()
+}
+
+// pruneRamps forgets the ramps of targets no longer in the configuration, where
+// loaded holds the keys of the targets loaded by this check
+func (s *Scheduler) pruneRamps(loaded map[string]bool) {
+	s.rampsMu.Lock()
+	defer s.rampsMu.Unlock()
+
+	for key := range s.ramps {
+		if !loaded[key] {
+			delete(s.ramps, key)
+		}
+	}
+}
+
+// nextRampStep computes the next replica count. With a duration the count follows
+// a straight line from the ramp's starting count to its target; a step size caps
+// how far a single step may move.
+func nextRampStep(current int32, state *rampState, stepSize int32, duration time.Duration, now time.Time) int32 {
+	next := state.target
+	if duration > 0 {
+		elapsed := now.Sub(state.started)
+		if elapsed < duration {
+			progress := float64(elapsed) / float64(duration)
+			next = state.from + int32(float64(state.target-state.from)*progress)
+		}
+		// Never step back towards the starting count
+		if (state.target > current && next < current) || (state.target < current && next > current) {
+			next = current
+		}
+	}
+
+	if stepSize > 0 {
+		if next > current+stepSize {
+			next = current + stepSize
+		} else if next < current-stepSize {
+			next = current - stepSize
+		}
+	}
+	return next
+}
+
+// targetEvent records an event on the resource's target if a recorder is configured,
+// naming where the resource was loaded from
+func (s *Scheduler) targetEvent(res *model.Resource, eventType, reason, message string) {
+	if s.recorder == nil {
+		return
+	}
+	message += fromSource(res)
+	apiVersion := res.Target.APIVersion
+	if gvk, err := res.Target.GroupVersionKind(); err == nil {
+		apiVersion = gvk.GroupVersion().String()
+	}
+	s.recorder.Event(&corev1.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       res.Target.Kind,
+		Namespace:  res.Namespace,
+		Name:       res.Target.Name,
+	}, eventType, reason, message)
+}
@@ -0,0 +1,276 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestScheduler_Apply_Ramp(t *testing.T) {
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name string
+		ramp model.RampPolicy
+		// steps are seconds after the window start and the replicas expected after each Apply
+		steps []struct {
+			offset   int64
+			replicas int64
+		}
+	}{
+		{
+			name: "step size and interval",
+			ramp: model.RampPolicy{StepSize: 3, StepInterval: "1m"},
+			steps: []struct {
+				offset   int64
+				replicas int64
+			}{
+				{0, 5},
+				{30, 5},
+				{60, 8},
+				{120, 10},
+				{180, 10},
+			},
+		},
+		{
+			name: "linear over a duration",
+			ramp: model.RampPolicy{Duration: "8m"},
+			steps: []struct {
+				offset   int64
+				replicas int64
+			}{
+				{0, 2},
+				{120, 4},
+				{240, 6},
+				{600, 10},
+			},
+		},
+		{
+			name: "duration capped by step size",
+			ramp: model.RampPolicy{StepSize: 1, Duration: "8m"},
+			steps: []struct {
+				offset   int64
+				replicas int64
+			}{
+				{0, 2},
+				{240, 3},
+				{480, 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ramp := tt.ramp
+			res := model.Resource{
+				Name:      "test-scaler",
+				Namespace: "default",
+				Target: model.Target{
+					Name: "test-deployment",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				Windows: []model.ScalingWindow{
+					{
+						StartTime: now,
+						EndTime:   now + 3600,
+						Replicas:  10,
+						Ramp:      &ramp,
+					},
+				},
+			}
+
+			mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 2))
+			recorder := record.NewFakeRecorder(10)
+			s, err := New(&mockProvider{}, Options{
+				PollInterval:  time.Second,
+				Logger:        newTestLogger(),
+				Client:        fake.NewSimpleClientset(),
+				Mapper:        mapper,
+				DynamicClient: dynamicClient,
+				ScaleClient:   scaleClient,
+				Recorder:      recorder,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create scheduler: %v", err)
+			}
+
+			deployments := appsv1.SchemeGroupVersion.WithResource("deployments")
+			for _, step := range tt.steps {
+				if _, err := s.Apply(context.Background(), &res, now+step.offset); err != nil {
+					t.Fatalf("Apply() at +%ds failed: %v", step.offset, err)
+				}
+				replicas, _ := getReplicas(t, dynamicClient, deployments, "default", "test-deployment")
+				if replicas != step.replicas {
+					t.Errorf("at +%ds: replicas = %d, want %d", step.offset, replicas, step.replicas)
+				}
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, "Ramping") {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				t.Error("expected a Ramping event")
+			}
+		})
+	}
+}
+
+func TestScheduler_Apply_RampPushedChecks(t *testing.T) {
+	now := time.Now().Unix()
+	res := model.Resource{
+		Name:             "test-scaler",
+		Namespace:        "default",
+		Target:           model.Target{Name: "test-deployment", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Windows: []model.ScalingWindow{
+			{StartTime: now, EndTime: now + 3600, Replicas: 10, Ramp: &model.RampPolicy{StepSize: 1}},
+		},
+	}
+
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 2))
+	s, err := New(&mockProvider{}, Options{
+		PollInterval:  30 * time.Second,
+		Logger:        newTestLogger(),
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	// Two configuration changes pushed within a poll interval do not step the ramp again
+	deployments := appsv1.SchemeGroupVersion.WithResource("deployments")
+	for _, step := range []struct {
+		offset   int64
+		replicas int64
+	}{
+		{0, 3},
+		{5, 3},
+		{12, 3},
+		{30, 4},
+		{60, 5},
+	} {
+		if _, err := s.Apply(context.Background(), &res, now+step.offset); err != nil {
+			t.Fatalf("Apply() at +%ds failed: %v", step.offset, err)
+		}
+		replicas, _ := getReplicas(t, dynamicClient, deployments, "default", "test-deployment")
+		if replicas != step.replicas {
+			t.Errorf("at +%ds: replicas = %d, want %d", step.offset, replicas, step.replicas)
+		}
+	}
+}
+
+func TestScheduler_Apply_RampLeavesWindowImmediately(t *testing.T) {
+	now := time.Now().Unix()
+	res := model.Resource{
+		Name:      "test-scaler",
+		Namespace: "default",
+		Target: model.Target{
+			Name: "test-deployment",
+			Kind: "Deployment",
+		},
+		OriginalReplicas: 2,
+		Windows: []model.ScalingWindow{
+			{
+				StartTime: now,
+				EndTime:   now + 3600,
+				Replicas:  10,
+				Ramp:      &model.RampPolicy{StepSize: 1},
+			},
+		},
+	}
+
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 2))
+	s, err := New(&mockProvider{}, Options{
+		PollInterval:  time.Second,
+		Logger:        newTestLogger(),
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, offset := range []int64{0, 60} {
+		if _, err := s.Apply(ctx, &res, now+offset); err != nil {
+			t.Fatalf("Apply() in window failed: %v", err)
+		}
+	}
+
+	replicas, err := s.Apply(ctx, &res, now+3600)
+	if err != nil {
+		t.Fatalf("Apply() after window failed: %v", err)
+	}
+	if replicas != 2 {
+		t.Errorf("Apply() after window = %d, want 2", replicas)
+	}
+	s.rampsMu.Lock()
+	defer s.rampsMu.Unlock()
+	if len(s.ramps) != 0 {
+		t.Errorf("ramps after window = %v, want the ramp forgotten", s.ramps)
+	}
+}
+
+func TestScheduler_CheckAndScale_RampPruned(t *testing.T) {
+	now := time.Now().Unix()
+	provider := &mockProvider{resources: []model.Resource{{
+		Name:             "test-scaler",
+		Namespace:        "default",
+		Target:           model.Target{Name: "test-deployment", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Windows: []model.ScalingWindow{
+			{StartTime: now - 60, EndTime: now + 3600, Replicas: 10, Ramp: &model.RampPolicy{StepSize: 1}},
+		},
+	}}}
+
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 2))
+	s, err := New(provider, Options{
+		PollInterval:  time.Second,
+		Logger:        newTestLogger(),
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	rampCount := func() int {
+		s.rampsMu.Lock()
+		defer s.rampsMu.Unlock()
+		return len(s.ramps)
+	}
+
+	if err := s.checkAndScale(context.Background()); err != nil {
+		t.Fatalf("checkAndScale() error = %v", err)
+	}
+	if got := rampCount(); got != 1 {
+		t.Fatalf("ramps after first check = %d, want 1", got)
+	}
+
+	// Removing the resource from the configuration forgets its ramp
+	provider.mu.Lock()
+	provider.resources = nil
+	provider.mu.Unlock()
+	if err := s.checkAndScale(context.Background()); err != nil {
+		t.Fatalf("checkAndScale() error = %v", err)
+	}
+	if got := rampCount(); got != 0 {
+		t.Errorf("ramps after the resource was removed = %d, want 0", got)
+	}
+}
@@ -2,16 +2,24 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/berkayuckac/k8schedul8r/pkg/config"
 	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/scale"
+	"k8s.io/client-go/tools/record"
 )
 
 // Logger interface allows for custom logging implementations
@@ -34,11 +42,27 @@ func (l *stdLogger) Println(v ...interface{}) {
 // Scheduler manages the time-based scaling of resources
 type Scheduler struct {
 	provider     config.Provider
+	calendars    config.CalendarProvider
+	holidays     map[string]model.Calendar
+	holidaysMu   sync.RWMutex
 	pollInterval time.Duration
 	stopCh       chan struct{}
 	stopOnce     sync.Once
+	runMu        sync.Mutex // orders Start joining wg against Stop closing stopCh
 	logger       Logger
 	client       kubernetes.Interface
+	mapper       meta.RESTMapper
+	scales       scale.ScalesGetter
+	dynamic      dynamic.Interface
+	recorder     record.EventRecorder
+	ramps        map[string]*rampState
+	revision     config.Revision
+	health       map[string]config.Health
+	conflicts    map[string]bool
+	warnings     map[string]string // warnings last logged for each resource
+	stale        map[string]string
+	staleMu      sync.Mutex
+	rampsMu      sync.Mutex
 	wg           sync.WaitGroup
 }
 
@@ -50,6 +74,18 @@ type Options struct {
 	Logger Logger
 	// Kubernetes client to use, if nil an in-cluster client will be created
 	Client kubernetes.Interface
+	// Config used to build any client not set here, if nil the in-cluster config is used
+	Config *rest.Config
+	// Mapper resolves target kinds to resources, if nil one is built on discovery
+	Mapper meta.RESTMapper
+	// ScaleClient reads and updates the /scale subresource of targets
+	ScaleClient scale.ScalesGetter
+	// DynamicClient reads and annotates targets of any kind
+	DynamicClient dynamic.Interface
+	// Calendars provides the holiday calendars resources can reference, optional
+	Calendars config.CalendarProvider
+	// Recorder emits events on scaling targets, optional
+	Recorder record.EventRecorder
 }
 
 // New creates a new scheduler instance
@@ -61,26 +97,62 @@ func New(provider config.Provider, opts Options) (*Scheduler, error) {
 		opts.Logger = &stdLogger{}
 	}
 
-	var client kubernetes.Interface
-	if opts.Client != nil {
-		client = opts.Client
-	} else {
-		config, err := rest.InClusterConfig()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get in-cluster config: %w", err)
+	cfg := opts.Config
+	client := opts.Client
+	if client == nil {
+		if cfg == nil {
+			var err error
+			cfg, err = rest.InClusterConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get in-cluster config: %w", err)
+			}
 		}
-		client, err = kubernetes.NewForConfig(config)
+		var err error
+		client, err = kubernetes.NewForConfig(cfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
 		}
 	}
 
+	mapper := opts.Mapper
+	if mapper == nil {
+		mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(client.Discovery()))
+	}
+
+	scales := opts.ScaleClient
+	if scales == nil && cfg != nil {
+		var err error
+		scales, err = scale.NewForConfig(rest.CopyConfig(cfg), mapper, dynamic.LegacyAPIPathResolverFunc,
+			scale.NewDiscoveryScaleKindResolver(client.Discovery()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create scale client: %w", err)
+		}
+	}
+
+	dynamicClient := opts.DynamicClient
+	if dynamicClient == nil && cfg != nil {
+		var err error
+		dynamicClient, err = dynamic.NewForConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+	}
+
 	return &Scheduler{
 		provider:     provider,
+		calendars:    opts.Calendars,
 		pollInterval: opts.PollInterval,
 		stopCh:       make(chan struct{}),
 		logger:       opts.Logger,
 		client:       client,
+		mapper:       mapper,
+		scales:       scales,
+		dynamic:      dynamicClient,
+		recorder:     opts.Recorder,
+		ramps:        make(map[string]*rampState),
+		stale:        make(map[string]string),
+		conflicts:    make(map[string]bool),
+		warnings:     make(map[string]string),
 	}, nil
 }
 
@@ -88,12 +160,28 @@ func New(provider config.Provider, opts Options) (*Scheduler, error) {
 func (s *Scheduler) Start(ctx context.Context) error {
 	s.logger.Printf("Starting scheduler with poll interval: %v", s.pollInterval)
 
+	// Join wg unless Stop already ran, so Stop never waits on a Start it missed
+	s.runMu.Lock()
+	select {
+	case <-s.stopCh:
+		s.runMu.Unlock()
+		return nil
+	default:
+	}
 	s.wg.Add(1)
+	s.runMu.Unlock()
 	defer s.wg.Done()
 
 	ticker := time.NewTicker(s.pollInterval)
 	defer ticker.Stop()
 
+	// Check as soon as a provider that pushes changes reports one, polling as the fallback
+	var changes <-chan struct{}
+	if watcher, ok := s.provider.(config.WatchProvider); ok {
+		changes = watcher.Subscribe()
+		defer watcher.Unsubscribe(changes)
+	}
+
 	// Do initial check immediately
 	if err := s.checkAndScale(ctx); err != nil {
 		s.logger.Printf("Initial scaling check failed: %v", err)
@@ -111,6 +199,15 @@ func (s *Scheduler) Start(ctx context.Context) error {
 			if err := s.checkAndScale(ctx); err != nil {
 				s.logger.Printf("Scaling check failed: %v", err)
 			}
+		case _, ok := <-changes:
+			if !ok {
+				// The provider stopped, keep polling
+				changes = nil
+				continue
+			}
+			if err := s.checkAndScale(ctx); err != nil {
+				s.logger.Printf("Scaling check after configuration change failed: %v", err)
+			}
 		}
 	}
 }
@@ -118,10 +215,12 @@ func (s *Scheduler) Start(ctx context.Context) error {
 // Stop gracefully stops the scheduler and waits for all operations to complete
 func (s *Scheduler) Stop() {
 	s.stopOnce.Do(func() {
+		s.runMu.Lock()
 		close(s.stopCh)
-		// If using a remote provider, stop it as well
-		if remoteProvider, ok := s.provider.(*config.RemoteProvider); ok {
-			remoteProvider.Stop()
+		s.runMu.Unlock()
+		// If using a provider with background work, stop it as well
+		if provider, ok := s.provider.(config.StopProvider); ok {
+			provider.Stop()
 		}
 	})
 	s.wg.Wait()
@@ -131,93 +230,306 @@ func (s *Scheduler) Stop() {
 func (s *Scheduler) checkAndScale(ctx context.Context) error {
 	// Load configuration
 	resources, err := s.provider.Load(true)
+	s.logHealth()
 	if err != nil {
-		s.logger.Printf("Configuration load failed: %v", err)
-		return fmt.Errorf("failed to load configuration: %w", err)
+		// Resources from the providers that loaded still apply when others failed
+		var multiErr *config.MultiError
+		if !errors.As(err, &multiErr) || !multiErr.Partial() {
+			s.logger.Printf("Configuration load failed: %v", err)
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		s.logger.Printf("Configuration partially loaded, scaling the resources that did: %v", err)
 	}
 
+	s.logRevision()
+	s.logConflicts()
+	s.logWarnings(resources)
+
 	if len(resources) == 0 {
 		s.logger.Println("No resources loaded")
+		s.pruneStale(nil)
+		s.pruneRamps(nil)
 		return nil
 	}
 
+	s.refreshCalendars()
+
 	now := time.Now().Unix()
 
 	// Process each resource
+	loaded := make(map[string]bool, len(resources))
 	for _, res := range resources {
-		desiredReplicas := res.GetDesiredReplicas(now)
-		s.logger.Printf("Resource %s/%s: desired replicas: %d", res.Namespace, res.Name, desiredReplicas)
+		loaded[rampKey(&res)] = true
+		applied, ok := s.applyStalePolicy(&res)
+		if !ok {
+			continue
+		}
+		res = applied
 
-		if err := s.ScaleResource(ctx, &res, desiredReplicas); err != nil {
-			s.logger.Printf("Failed to scale %s/%s: %v", res.Namespace, res.Name, err)
+		replicas, err := s.Apply(ctx, &res, now)
+		if err != nil {
+			s.logger.Printf("Failed to scale %s/%s%s: %v", res.Namespace, res.Name, fromSource(&res), err)
 			continue
 		}
 
-		s.logger.Printf("Successfully scaled %s %s/%s to %d replicas",
-			res.Target.Kind, res.Namespace, res.Target.Name, desiredReplicas)
+		s.logger.Printf("Successfully scaled %s %s/%s to %d replicas%s",
+			res.Target.Kind, res.Namespace, res.Target.Name, replicas, fromSource(&res))
 	}
+	s.pruneStale(loaded)
+	s.pruneRamps(loaded)
 
 	return nil
 }
 
-// ScaleResource scales a kubernetes resource to the desired number of replicas
-func (s *Scheduler) ScaleResource(ctx context.Context, res *model.Resource, replicas int32) error {
-	switch res.Target.Kind {
-	case "Deployment":
-		return s.scaleDeployment(ctx, res.Target.Name, res.Namespace, replicas)
-	case "StatefulSet":
-		return s.scaleStatefulSet(ctx, res.Target.Name, res.Namespace, replicas)
-	default:
-		return fmt.Errorf("unsupported resource kind: %s", res.Target.Kind)
+// fromSource describes where the resource was loaded from, for appending to log and
+// event messages, or returns "" if its provider did not record it
+func fromSource(res *model.Resource) string {
+	if res.Provenance == nil {
+		return ""
 	}
+	return " (from " + res.Provenance.String() + ")"
 }
 
-// scaleDeployment scales a deployment to the desired number of replicas
-func (s *Scheduler) scaleDeployment(ctx context.Context, name, namespace string, replicas int32) error {
-	deployment, err := s.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get deployment: %w", err)
+// logRevision logs when the provider reports a new configuration revision or a failure to load one
+func (s *Scheduler) logRevision() {
+	provider, ok := s.provider.(config.RevisionProvider)
+	if !ok {
+		return
 	}
 
-	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == replicas {
-		s.logger.Printf("Deployment %s/%s already at %d replicas", namespace, name, replicas)
-		return nil
+	rev := provider.Revision()
+	if rev.Generation != s.revision.Generation && rev.Generation > 0 {
+		if rev.Commit != "" {
+			s.logger.Printf("Configuration revision %d (commit %.12s) now in effect", rev.Generation, rev.Commit)
+		} else {
+			s.logger.Printf("Configuration revision %d (%.12s) now in effect", rev.Generation, rev.Hash)
+		}
+	}
+	if rev.Error != nil && (s.revision.Error == nil || rev.Error.Error() != s.revision.Error.Error()) {
+		s.logger.Printf("Configuration reload failed, keeping revision %d: %v", rev.Generation, rev.Error)
+	}
+	s.revision = rev
+}
+
+// logHealth logs when a configuration source starts failing or fails differently, trips
+// its circuit breaker or recovers. The sources of a provider combining several are
+// tracked separately.
+func (s *Scheduler) logHealth() {
+	if s.health == nil {
+		s.health = make(map[string]config.Health)
+	}
+	switch provider := s.provider.(type) {
+	case config.SourceHealthProvider:
+		for _, source := range provider.SourceHealth() {
+			s.logSourceHealth(source.Source, source.Health)
+		}
+	case config.HealthProvider:
+		s.logSourceHealth("", provider.Health())
+	}
+}
+
+// logSourceHealth logs changes in the health of the named source, or of the provider's
+// only source if source is empty
+func (s *Scheduler) logSourceHealth(source string, health config.Health) {
+	name := "Configuration source"
+	if source != "" {
+		name += " " + source
 	}
 
-	// Create a copy of the deployment to modify
-	deploymentCopy := deployment.DeepCopy()
-	deploymentCopy.Spec.Replicas = &replicas
+	previous := s.health[source]
+	switch {
+	case health.ConsecutiveFailures == 0 && previous.ConsecutiveFailures > 0:
+		s.logger.Printf("%s recovered after %d consecutive failures", name, previous.ConsecutiveFailures)
+	case health.LastError != nil && (previous.LastError == nil || health.LastError.Error() != previous.LastError.Error()):
+		lastSuccess := "never"
+		if !health.LastSuccess.IsZero() {
+			lastSuccess = health.LastSuccess.Format(time.RFC3339)
+		}
+		s.logger.Printf("%s failing (%d consecutive failures, last success %s): %v",
+			name, health.ConsecutiveFailures, lastSuccess, health.LastError)
+	}
+	if !health.CircuitOpenUntil.IsZero() && !health.CircuitOpenUntil.Equal(previous.CircuitOpenUntil) {
+		s.logger.Printf("%s circuit breaker open until %s", name, health.CircuitOpenUntil.Format(time.RFC3339))
+	}
+	s.health[source] = health
+}
 
-	_, err = s.client.AppsV1().Deployments(namespace).Update(ctx, deploymentCopy, metav1.UpdateOptions{})
+// logConflicts logs conflicts between providers when they are first resolved
+func (s *Scheduler) logConflicts() {
+	provider, ok := s.provider.(config.ConflictProvider)
+	if !ok {
+		return
+	}
+
+	conflicts := make(map[string]bool)
+	for _, conflict := range provider.Conflicts() {
+		message := conflict.String()
+		if !s.conflicts[message] {
+			s.logger.Printf("Warning: configuration conflict: %s", message)
+		}
+		conflicts[message] = true
+	}
+	s.conflicts = conflicts
+}
+
+// logWarnings logs the warnings Validate found for each resource when they differ
+// from those last logged for it, so each is logged once rather than on every check
+func (s *Scheduler) logWarnings(resources []model.Resource) {
+	logged := make(map[string]string, len(resources))
+	for i := range resources {
+		res := &resources[i]
+		key := res.Namespace + "/" + res.Name
+		warnings := strings.Join(res.Warnings, "\n")
+		logged[key] = warnings
+		if s.warnings[key] == warnings {
+			continue
+		}
+		for _, warning := range res.Warnings {
+			s.logger.Printf("Resource %s: warning: %s%s", key, warning, fromSource(res))
+		}
+	}
+	s.warnings = logged
+}
+
+// refreshCalendars reloads the holiday calendars, keeping the previous set on failure
+func (s *Scheduler) refreshCalendars() {
+	if s.calendars == nil {
+		return
+	}
+
+	calendars, err := s.calendars.LoadCalendars(true)
 	if err != nil {
-		return fmt.Errorf("failed to update deployment: %w", err)
+		s.logger.Printf("Calendar load failed, using previous calendars: %v", err)
+		return
 	}
 
-	s.logger.Printf("Successfully scaled deployment %s/%s", namespace, name)
-	return nil
+	holidays := make(map[string]model.Calendar, len(calendars))
+	for _, cal := range calendars {
+		holidays[cal.Name] = cal
+	}
+
+	s.holidaysMu.Lock()
+	s.holidays = holidays
+	s.holidaysMu.Unlock()
+}
+
+// DesiredReplicas resolves the resource's holiday calendar and returns the replica count for now
+func (s *Scheduler) DesiredReplicas(res *model.Resource, now int64) int32 {
+	if res.Calendar != "" && res.Holidays == nil {
+		s.holidaysMu.RLock()
+		cal, ok := s.holidays[res.Calendar]
+		s.holidaysMu.RUnlock()
+
+		if ok {
+			res.Holidays = &cal
+		} else {
+			s.logger.Printf("Resource %s/%s: calendar %s not found, ignoring it", res.Namespace, res.Name, res.Calendar)
+		}
+	}
+	return res.GetDesiredReplicas(now)
+}
+
+// Apply brings the resource's target to the replica count its schedule calls for at now
+// and returns the count that was applied
+func (s *Scheduler) Apply(ctx context.Context, res *model.Resource, now int64) (int32, error) {
+	desiredReplicas := s.DesiredReplicas(res, now)
+	if res.IsHoliday(now) {
+		s.logger.Printf("Resource %s/%s: calendar %s day, windows suppressed", res.Namespace, res.Name, res.Calendar)
+	}
+	s.logger.Printf("Resource %s/%s: desired replicas: %d", res.Namespace, res.Name, desiredReplicas)
+
+	if res.IsHPAMode() {
+		return s.applyHPA(ctx, res, now, desiredReplicas)
+	}
+
+	if window := res.ActiveWindow(now); window != nil && window.Ramp != nil && !res.IsHoliday(now) {
+		replicas, err := s.rampReplicas(ctx, res, window.Ramp, desiredReplicas, time.Unix(now, 0))
+		if err != nil {
+			return 0, err
+		}
+		desiredReplicas = replicas
+	} else {
+		// Leaving a ramped window is immediate and a later window starts a fresh ramp
+		s.clearRamp(res)
+	}
+
+	if res.CaptureBaseline {
+		return s.applyWithBaseline(ctx, res, res.IsScheduled(now), desiredReplicas)
+	}
+
+	if err := s.ScaleResource(ctx, res, desiredReplicas); err != nil {
+		return 0, err
+	}
+	return desiredReplicas, nil
 }
 
-// scaleStatefulSet scales a statefulset to the desired number of replicas
-func (s *Scheduler) scaleStatefulSet(ctx context.Context, name, namespace string, replicas int32) error {
-	statefulset, err := s.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+// ScaleResource scales a kubernetes resource to the desired number of replicas
+// through its /scale subresource. CronJob targets are suspended for 0 replicas
+// and resumed otherwise.
+func (s *Scheduler) ScaleResource(ctx context.Context, res *model.Resource, replicas int32) error {
+	if res.Target.IsCronJob() {
+		return s.suspendCronJob(ctx, res, replicas)
+	}
+	if s.scales == nil {
+		return fmt.Errorf("no scale client configured")
+	}
+
+	mapping, err := s.targetMapping(res)
 	if err != nil {
-		return fmt.Errorf("failed to get statefulset: %w", err)
+		return err
 	}
+	kind := strings.ToLower(res.Target.Kind)
+	scales := s.scales.Scales(res.Namespace)
 
-	if statefulset.Spec.Replicas != nil && *statefulset.Spec.Replicas == replicas {
-		s.logger.Printf("StatefulSet %s/%s already at %d replicas", namespace, name, replicas)
+	current, err := scales.Get(ctx, mapping.Resource.GroupResource(), res.Target.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s scale: %w", kind, err)
+	}
+
+	if current.Spec.Replicas == replicas {
+		s.logger.Printf("%s %s/%s already at %d replicas", res.Target.Kind, res.Namespace, res.Target.Name, replicas)
 		return nil
 	}
 
-	// Create a copy of the statefulset to modify
-	statefulsetCopy := statefulset.DeepCopy()
-	statefulsetCopy.Spec.Replicas = &replicas
+	current.Spec.Replicas = replicas
+	if _, err := scales.Update(ctx, mapping.Resource.GroupResource(), current, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update %s scale: %w", kind, err)
+	}
+
+	s.logger.Printf("Successfully scaled %s %s/%s to %d replicas", kind, res.Namespace, res.Target.Name, replicas)
+	return nil
+}
+
+// currentReplicas reads the target's replica count from its /scale subresource, or a CronJob's state
+func (s *Scheduler) currentReplicas(ctx context.Context, res *model.Resource) (int32, error) {
+	if res.Target.IsCronJob() {
+		return s.cronJobReplicas(ctx, res)
+	}
+	if s.scales == nil {
+		return 0, fmt.Errorf("no scale client configured")
+	}
+
+	mapping, err := s.targetMapping(res)
+	if err != nil {
+		return 0, err
+	}
 
-	_, err = s.client.AppsV1().StatefulSets(namespace).Update(ctx, statefulsetCopy, metav1.UpdateOptions{})
+	current, err := s.scales.Scales(res.Namespace).Get(ctx, mapping.Resource.GroupResource(), res.Target.Name, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to update statefulset: %w", err)
+		return 0, fmt.Errorf("failed to get %s scale: %w", strings.ToLower(res.Target.Kind), err)
 	}
+	return current.Spec.Replicas, nil
+}
 
-	s.logger.Printf("Successfully scaled statefulset %s/%s to %d replicas", namespace, name, replicas)
-	return nil
+// targetMapping resolves the resource's target kind to its REST mapping
+func (s *Scheduler) targetMapping(res *model.Resource) (*meta.RESTMapping, error) {
+	gvk, err := res.Target.GroupVersionKind()
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", gvk, err)
+	}
+	return mapping, nil
 }
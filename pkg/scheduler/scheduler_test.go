@@ -5,13 +5,24 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/berkayuckac/k8schedul8r/pkg/config"
 	"github.com/berkayuckac/k8schedul8r/pkg/model"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakescale "k8s.io/client-go/scale/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 // testLogger captures log output for testing
@@ -66,6 +77,16 @@ func (m *mockProvider) getLoadCount() int {
 	return m.loads
 }
 
+// mockCalendarProvider implements config.CalendarProvider for testing
+type mockCalendarProvider struct {
+	calendars []model.Calendar
+	err       error
+}
+
+func (m *mockCalendarProvider) LoadCalendars(validate bool) ([]model.Calendar, error) {
+	return m.calendars, m.err
+}
+
 func createTestDeployment(name, namespace string, replicas int32) *appsv1.Deployment {
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -78,6 +99,70 @@ func createTestDeployment(name, namespace string, replicas int32) *appsv1.Deploy
 	}
 }
 
+// rolloutGVK is a custom kind exposing /scale, standing in for e.g. Argo Rollouts
+var rolloutGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
+// fakeScaling returns a RESTMapper, dynamic client and scale client serving the
+// given objects, with the /scale subresource backed by each object's spec.replicas
+func fakeScaling(objects ...runtime.Object) (meta.RESTMapper, *dynamicfake.FakeDynamicClient, *fakescale.FakeScaleClient) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, kind := range []string{"Deployment", "StatefulSet", "ReplicaSet"} {
+		mapper.Add(appsv1.SchemeGroupVersion.WithKind(kind), meta.RESTScopeNamespace)
+	}
+	mapper.Add(rolloutGVK, meta.RESTScopeNamespace)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, objects...)
+	scaleClient := &fakescale.FakeScaleClient{}
+
+	getTarget := func(action k8stesting.Action, name string) (schema.GroupVersionResource, *unstructured.Unstructured, error) {
+		gvr, err := mapper.ResourceFor(action.GetResource().GroupResource().WithVersion(""))
+		if err != nil {
+			return gvr, nil, err
+		}
+		obj, err := dynamicClient.Resource(gvr).Namespace(action.GetNamespace()).Get(context.Background(), name, metav1.GetOptions{})
+		return gvr, obj, err
+	}
+
+	scaleClient.AddReactor("get", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		_, obj, err := getTarget(action, action.(k8stesting.GetAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		return true, &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: obj.GetName(), Namespace: obj.GetNamespace()},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: int32(replicas)},
+		}, nil
+	})
+	scaleClient.AddReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		scale := action.(k8stesting.UpdateAction).GetObject().(*autoscalingv1.Scale)
+		gvr, obj, err := getTarget(action, scale.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		if err := unstructured.SetNestedField(obj.Object, int64(scale.Spec.Replicas), "spec", "replicas"); err != nil {
+			return true, nil, err
+		}
+		if _, err := dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(context.Background(), obj, metav1.UpdateOptions{}); err != nil {
+			return true, nil, err
+		}
+		return true, scale, nil
+	})
+
+	return mapper, dynamicClient, scaleClient
+}
+
+// getReplicas reads spec.replicas of a target from the fake dynamic client
+func getReplicas(t *testing.T, client *dynamicfake.FakeDynamicClient, gvr schema.GroupVersionResource, namespace, name string) (int64, map[string]string) {
+	t.Helper()
+	obj, err := client.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get %s %s/%s: %v", gvr.Resource, namespace, name, err)
+	}
+	replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	return replicas, obj.GetAnnotations()
+}
+
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -156,6 +241,7 @@ func TestScheduler_Start(t *testing.T) {
 	// Create test deployment
 	deployment := createTestDeployment("test-deployment", "default", 2)
 	client := fake.NewSimpleClientset(deployment)
+	mapper, dynamicClient, scaleClient := fakeScaling(deployment)
 
 	tests := []struct {
 		name           string
@@ -201,9 +287,12 @@ func TestScheduler_Start(t *testing.T) {
 
 			logger := newTestLogger()
 			s, err := New(provider, Options{
-				PollInterval: tt.pollInterval,
-				Logger:       logger,
-				Client:       client,
+				PollInterval:  tt.pollInterval,
+				Logger:        logger,
+				Client:        client,
+				Mapper:        mapper,
+				DynamicClient: dynamicClient,
+				ScaleClient:   scaleClient,
 			})
 			if err != nil {
 				t.Fatalf("Failed to create scheduler: %v", err)
@@ -358,6 +447,7 @@ func TestScheduler_checkAndScale(t *testing.T) {
 	// Create test deployment
 	deployment := createTestDeployment("test-deployment", "default", 2)
 	client := fake.NewSimpleClientset(deployment)
+	mapper, dynamicClient, scaleClient := fakeScaling(deployment)
 
 	tests := []struct {
 		name           string
@@ -381,6 +471,30 @@ func TestScheduler_checkAndScale(t *testing.T) {
 				"Configuration load failed",
 			},
 		},
+		{
+			name: "evaluates cron windows against current time",
+			resources: []model.Resource{
+				{
+					Name:      "test-scaler",
+					Namespace: "default",
+					Target: model.Target{
+						Name: "test-deployment",
+						Kind: "Deployment",
+					},
+					OriginalReplicas: 2,
+					Windows: []model.ScalingWindow{
+						{
+							Schedule: "* * * * *", // opens every minute
+							Duration: "1h",
+							Replicas: 7,
+						},
+					},
+				},
+			},
+			wantLogEntries: []string{
+				"desired replicas: 7",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -392,9 +506,12 @@ func TestScheduler_checkAndScale(t *testing.T) {
 
 			logger := newTestLogger()
 			s, err := New(provider, Options{
-				PollInterval: time.Second,
-				Logger:       logger,
-				Client:       client,
+				PollInterval:  time.Second,
+				Logger:        logger,
+				Client:        client,
+				Mapper:        mapper,
+				DynamicClient: dynamicClient,
+				ScaleClient:   scaleClient,
 			})
 			if err != nil {
 				t.Fatalf("Failed to create scheduler: %v", err)
@@ -428,3 +545,467 @@ func TestScheduler_checkAndScale(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduler_DesiredReplicas_Calendar(t *testing.T) {
+	now := time.Now().Unix()
+	today := time.Unix(now, 0).UTC().Format("2006-01-02")
+	holidayReplicas := int32(1)
+
+	newResource := func(calendar string) model.Resource {
+		return model.Resource{
+			Name:             "test-scaler",
+			Namespace:        "default",
+			OriginalReplicas: 2,
+			Calendar:         calendar,
+			HolidayReplicas:  &holidayReplicas,
+			Windows: []model.ScalingWindow{
+				{
+					StartTime: now - 3600,
+					EndTime:   now + 3600,
+					Replicas:  5,
+				},
+			},
+		}
+	}
+
+	logger := newTestLogger()
+	s, err := New(&mockProvider{}, Options{
+		PollInterval: time.Second,
+		Logger:       logger,
+		Client:       fake.NewSimpleClientset(),
+		Calendars: &mockCalendarProvider{
+			calendars: []model.Calendar{
+				{Name: "holidays", Days: []model.CalendarDay{{Date: today}}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	s.refreshCalendars()
+
+	res := newResource("holidays")
+	if got := s.DesiredReplicas(&res, now); got != holidayReplicas {
+		t.Errorf("DesiredReplicas() on calendar day = %d, want %d", got, holidayReplicas)
+	}
+
+	res = newResource("missing")
+	if got := s.DesiredReplicas(&res, now); got != 5 {
+		t.Errorf("DesiredReplicas() with unknown calendar = %d, want 5", got)
+	}
+
+	found := false
+	for _, entry := range logger.getEntries() {
+		if strings.Contains(entry, "calendar missing not found") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Error("Log entry not found: calendar missing not found")
+	}
+}
+
+func TestScheduler_ScaleResource_Kinds(t *testing.T) {
+	replicas := int32(2)
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-replicaset", Namespace: "default"},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}
+	rollout := &unstructured.Unstructured{}
+	rollout.SetGroupVersionKind(rolloutGVK)
+	rollout.SetName("test-rollout")
+	rollout.SetNamespace("default")
+	_ = unstructured.SetNestedField(rollout.Object, int64(2), "spec", "replicas")
+
+	mapper, dynamicClient, scaleClient := fakeScaling(
+		createTestDeployment("test-deployment", "default", 2),
+		replicaSet,
+		rollout,
+	)
+	s, err := New(&mockProvider{}, Options{
+		PollInterval:  time.Second,
+		Logger:        newTestLogger(),
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		target  model.Target
+		gvr     schema.GroupVersionResource
+		wantErr string
+	}{
+		{
+			name:   "deployment with default apiVersion",
+			target: model.Target{Name: "test-deployment", Kind: "Deployment"},
+			gvr:    appsv1.SchemeGroupVersion.WithResource("deployments"),
+		},
+		{
+			name:   "replicaset",
+			target: model.Target{Name: "test-replicaset", Kind: "ReplicaSet", APIVersion: "apps/v1"},
+			gvr:    appsv1.SchemeGroupVersion.WithResource("replicasets"),
+		},
+		{
+			name:   "custom resource exposing scale",
+			target: model.Target{Name: "test-rollout", Kind: "Rollout", APIVersion: "argoproj.io/v1alpha1"},
+			gvr:    rolloutGVK.GroupVersion().WithResource("rollouts"),
+		},
+		{
+			name:    "unknown kind",
+			target:  model.Target{Name: "test-widget", Kind: "Widget", APIVersion: "example.com/v1"},
+			wantErr: "failed to resolve",
+		},
+		{
+			name:    "custom kind without apiVersion",
+			target:  model.Target{Name: "test-rollout", Kind: "Rollout"},
+			wantErr: "apiVersion is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &model.Resource{Name: "test-scaler", Namespace: "default", Target: tt.target}
+			err := s.ScaleResource(context.Background(), res, 6)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ScaleResource() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScaleResource() failed: %v", err)
+			}
+			if got, _ := getReplicas(t, dynamicClient, tt.gvr, "default", tt.target.Name); got != 6 {
+				t.Errorf("replicas = %d, want 6", got)
+			}
+		})
+	}
+}
+
+// healthProvider is a mockProvider reporting the health of its source
+type healthProvider struct {
+	mockProvider
+	health config.Health
+}
+
+func (h *healthProvider) Health() config.Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.health
+}
+
+func (h *healthProvider) setHealth(health config.Health) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.health = health
+}
+
+func TestScheduler_LogHealth(t *testing.T) {
+	logger := newTestLogger()
+	provider := &healthProvider{}
+	s := &Scheduler{provider: provider, logger: logger}
+
+	lastSuccess := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	openUntil := time.Date(2025, 1, 6, 9, 5, 0, 0, time.UTC)
+	steps := []struct {
+		health  config.Health
+		wantLog string
+	}{
+		{
+			health: config.Health{LastSuccess: lastSuccess},
+		},
+		{
+			health:  config.Health{LastSuccess: lastSuccess, LastError: fmt.Errorf("connection refused"), ConsecutiveFailures: 1},
+			wantLog: "Configuration source failing (1 consecutive failures, last success 2025-01-06T09:00:00Z): connection refused",
+		},
+		{
+			// The same error again is not logged
+			health: config.Health{LastSuccess: lastSuccess, LastError: fmt.Errorf("connection refused"), ConsecutiveFailures: 2},
+		},
+		{
+			health:  config.Health{LastSuccess: lastSuccess, LastError: fmt.Errorf("connection refused"), ConsecutiveFailures: 3, CircuitOpenUntil: openUntil},
+			wantLog: "Configuration source circuit breaker open until 2025-01-06T09:05:00Z",
+		},
+		{
+			health:  config.Health{LastSuccess: openUntil},
+			wantLog: "Configuration source recovered after 3 consecutive failures",
+		},
+	}
+
+	for i, step := range steps {
+		before := len(logger.getEntries())
+		provider.setHealth(step.health)
+		s.logHealth()
+
+		entries := logger.getEntries()[before:]
+		if step.wantLog == "" {
+			if len(entries) != 0 {
+				t.Errorf("step %d logged %q, want nothing", i, entries)
+			}
+			continue
+		}
+		if len(entries) != 1 || entries[0] != step.wantLog {
+			t.Errorf("step %d logged %q, want %q", i, entries, step.wantLog)
+		}
+	}
+}
+
+type conflictProvider struct {
+	mockProvider
+	conflicts []config.Conflict
+}
+
+func (c *conflictProvider) Conflicts() []config.Conflict {
+	return c.conflicts
+}
+
+func TestScheduler_LogConflicts(t *testing.T) {
+	logger := newTestLogger()
+	conflict := config.Conflict{
+		Target:  "default/Deployment/web",
+		Sources: []string{"file /etc/config.yaml", "remote https://config"},
+		Policy:  config.ConflictPolicyPrecedence,
+	}
+	provider := &conflictProvider{conflicts: []config.Conflict{conflict}}
+	s := &Scheduler{provider: provider, logger: logger}
+
+	want := "Warning: configuration conflict: default/Deployment/web is targeted by file /etc/config.yaml and remote https://config, using file /etc/config.yaml"
+	s.logConflicts()
+	if entries := logger.getEntries(); len(entries) != 1 || entries[0] != want {
+		t.Fatalf("logConflicts() logged %q, want %q", entries, want)
+	}
+
+	// An ongoing conflict is not logged again, but one that returns after being resolved is
+	s.logConflicts()
+	provider.conflicts = nil
+	s.logConflicts()
+	provider.conflicts = []config.Conflict{conflict}
+	s.logConflicts()
+	if entries := logger.getEntries(); len(entries) != 2 {
+		t.Errorf("logConflicts() logged %q, want the conflict twice", entries)
+	}
+}
+
+func TestScheduler_CheckAndScale_PartialFailure(t *testing.T) {
+	now := time.Now().Unix()
+	healthy := &mockProvider{resources: []model.Resource{{
+		Name:             "test-scaler",
+		Namespace:        "default",
+		Target:           model.Target{Name: "test-deployment", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Windows: []model.ScalingWindow{
+			{StartTime: now - 3600, EndTime: now + 3600, Replicas: 5},
+		},
+	}}}
+	failing := &mockProvider{err: fmt.Errorf("connection refused")}
+	provider, err := config.NewMultiProvider(config.MultiProviderConfig{}, failing, healthy)
+	if err != nil {
+		t.Fatalf("NewMultiProvider() error = %v", err)
+	}
+
+	logger := newTestLogger()
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 3))
+	s, err := New(provider, Options{
+		PollInterval:  time.Second,
+		Logger:        logger,
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	if err := s.checkAndScale(context.Background()); err != nil {
+		t.Fatalf("checkAndScale() error = %v", err)
+	}
+
+	deployments := appsv1.SchemeGroupVersion.WithResource("deployments")
+	if replicas, _ := getReplicas(t, dynamicClient, deployments, "default", "test-deployment"); replicas != 5 {
+		t.Errorf("replicas = %d, want 5 from the healthy provider", replicas)
+	}
+	if !containsEntry(logger.getEntries(), "Configuration partially loaded") {
+		t.Errorf("log entries %q do not report the partial load", logger.getEntries())
+	}
+	if !containsEntry(logger.getEntries(), "Configuration source *scheduler.mockProvider failing (1 consecutive failures, last success never): connection refused") {
+		t.Errorf("log entries %q do not report the failing source", logger.getEntries())
+	}
+}
+
+func TestScheduler_CheckAndScale_Provenance(t *testing.T) {
+	now := time.Now().Unix()
+	provider := &mockProvider{resources: []model.Resource{{
+		Name:             "test-scaler",
+		Namespace:        "default",
+		Target:           model.Target{Name: "test-deployment", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Windows: []model.ScalingWindow{
+			{StartTime: now - 3600, EndTime: now + 3600, Replicas: 5},
+		},
+		Provenance: &model.Provenance{Provider: model.ProviderGit, Source: "https://git/schedules.git@main:web.yaml", Revision: "4f2c9a1b7e0d3c5a"},
+	}}}
+
+	logger := newTestLogger()
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 3))
+	s, err := New(provider, Options{
+		PollInterval:  time.Second,
+		Logger:        logger,
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	if err := s.checkAndScale(context.Background()); err != nil {
+		t.Fatalf("checkAndScale() error = %v", err)
+	}
+
+	want := "Successfully scaled Deployment default/test-deployment to 5 replicas (from git https://git/schedules.git@main:web.yaml (revision 4f2c9a1b7e0d))"
+	if !containsEntry(logger.getEntries(), want) {
+		t.Errorf("log entries %q do not contain %q", logger.getEntries(), want)
+	}
+}
+
+// countingCRDProvider counts the loads of a CRDProvider
+type countingCRDProvider struct {
+	*config.CRDProvider
+	loads atomic.Int32
+}
+
+func (c *countingCRDProvider) Load(validate bool) ([]model.Resource, error) {
+	c.loads.Add(1)
+	return c.CRDProvider.Load(validate)
+}
+
+func TestScheduler_Start_WatchProvider(t *testing.T) {
+	crd, err := config.NewCRDProvider(config.CRDConfig{Namespace: "default"}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewCRDProvider() error = %v", err)
+	}
+	provider := &countingCRDProvider{CRDProvider: crd}
+
+	logger := newTestLogger()
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 2))
+	s, err := New(provider, Options{
+		// Long enough that only the change notification can trigger the check
+		PollInterval:  time.Hour,
+		Logger:        logger,
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx)
+
+	// Wait for the initial check before changing the configuration
+	deadline := time.Now().Add(5 * time.Second)
+	for provider.loads.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("scheduler did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	now := time.Now().Unix()
+	provider.UpdateResource(model.Resource{
+		Name:             "test-scaler",
+		Namespace:        "default",
+		Target:           model.Target{Name: "test-deployment", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Windows: []model.ScalingWindow{
+			{StartTime: now - 3600, EndTime: now + 3600, Replicas: 5},
+		},
+	})
+
+	want := "Successfully scaled Deployment default/test-deployment to 5 replicas"
+	for !containsEntry(logger.getEntries(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("log entries %q do not contain %q", logger.getEntries(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestScheduler_LogWarnings(t *testing.T) {
+	now := time.Now().Unix()
+	resource := model.Resource{
+		Name:             "test-scaler",
+		Namespace:        "default",
+		Target:           model.Target{Name: "test-deployment", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Windows: []model.ScalingWindow{
+			{StartTime: now - 3600, EndTime: now + 3600, Replicas: 5},
+			{StartTime: now, EndTime: now + 7200, Replicas: 3},
+		},
+	}
+	// Providers validate what they load, which records the overlap warnings
+	if err := resource.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	provider := &mockProvider{resources: []model.Resource{resource}}
+
+	logger := newTestLogger()
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 2))
+	s, err := New(provider, Options{
+		PollInterval:  time.Second,
+		Logger:        logger,
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	countWarnings := func() int {
+		count := 0
+		for _, entry := range logger.getEntries() {
+			if strings.Contains(entry, "Resource default/test-scaler: warning: windows 0 and 1 overlap") {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The warning is logged once, however many checks see the same windows
+	for i := 0; i < 3; i++ {
+		if err := s.checkAndScale(context.Background()); err != nil {
+			t.Fatalf("checkAndScale() error = %v", err)
+		}
+	}
+	if got := countWarnings(); got != 1 {
+		t.Errorf("overlap warning logged %d times, want once", got)
+	}
+
+	// A warning that goes away and comes back is logged again
+	for _, policy := range []string{model.OverlapPolicyMaxReplicas, ""} {
+		provider.mu.Lock()
+		provider.resources[0].OverlapPolicy = policy
+		if err := provider.resources[0].Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+		provider.mu.Unlock()
+		if err := s.checkAndScale(context.Background()); err != nil {
+			t.Fatalf("checkAndScale() error = %v", err)
+		}
+	}
+	if got := countWarnings(); got != 2 {
+		t.Errorf("overlap warning logged %d times after it came back, want 2", got)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// applyStalePolicy returns the resource to apply under its stale policy, or false if
+// the resource should not be scaled. The first time a resource turns stale, and when
+// it is refreshed again, this is logged and recorded as an event on the target.
+func (s *Scheduler) applyStalePolicy(res *model.Resource) (model.Resource, bool) {
+	key := rampKey(res)
+
+	s.staleMu.Lock()
+	policy, wasStale := s.stale[key]
+	if res.Stale == nil {
+		delete(s.stale, key)
+	} else {
+		s.stale[key] = res.Stale.Policy
+	}
+	s.staleMu.Unlock()
+
+	if res.Stale == nil {
+		if wasStale {
+			message := fmt.Sprintf("Configuration refreshed, %s stale policy lifted", policy)
+			s.logger.Printf("Resource %s/%s: %s", res.Namespace, res.Name, message)
+			s.targetEvent(res, corev1.EventTypeNormal, "ConfigurationRefreshed", message)
+		}
+		return *res, true
+	}
+
+	if !wasStale || policy != res.Stale.Policy {
+		message := fmt.Sprintf("Configuration not refreshed since %s, applying %s stale policy",
+			res.Stale.FetchedAt.Format(time.RFC3339), res.Stale.Policy)
+		s.logger.Printf("Resource %s/%s: %s", res.Namespace, res.Name, message)
+		s.targetEvent(res, corev1.EventTypeWarning, "StaleConfiguration", message)
+	}
+
+	switch res.Stale.Policy {
+	case model.StalePolicyFreeze:
+		return *res, false
+	case model.StalePolicyRevert:
+		return res.Reverted(), true
+	default:
+		return *res, true
+	}
+}
+
+// pruneStale forgets the stale policies of targets no longer in the configuration,
+// where loaded holds the keys of the targets loaded by this check
+func (s *Scheduler) pruneStale(loaded map[string]bool) {
+	s.staleMu.Lock()
+	defer s.staleMu.Unlock()
+
+	for key := range s.stale {
+		if !loaded[key] {
+			delete(s.stale, key)
+		}
+	}
+}
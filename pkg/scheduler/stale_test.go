@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berkayuckac/k8schedul8r/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestScheduler_CheckAndScale_StalePolicy(t *testing.T) {
+	now := time.Now().Unix()
+	fetchedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		policy       string
+		wantReplicas int64
+	}{
+		{
+			name:         "keep applies the schedule",
+			policy:       model.StalePolicyKeep,
+			wantReplicas: 5,
+		},
+		{
+			name:         "freeze leaves the target alone",
+			policy:       model.StalePolicyFreeze,
+			wantReplicas: 3,
+		},
+		{
+			name:         "revert returns to original replicas",
+			policy:       model.StalePolicyRevert,
+			wantReplicas: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &mockProvider{resources: []model.Resource{{
+				Name:      "test-scaler",
+				Namespace: "default",
+				Target: model.Target{
+					Name: "test-deployment",
+					Kind: "Deployment",
+				},
+				OriginalReplicas: 2,
+				Windows: []model.ScalingWindow{
+					{StartTime: now - 3600, EndTime: now + 3600, Replicas: 5},
+				},
+				Stale: &model.Staleness{Policy: tt.policy, FetchedAt: fetchedAt},
+			}}}
+
+			logger := newTestLogger()
+			mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 3))
+			recorder := record.NewFakeRecorder(10)
+			s, err := New(provider, Options{
+				PollInterval:  time.Second,
+				Logger:        logger,
+				Client:        fake.NewSimpleClientset(),
+				Mapper:        mapper,
+				DynamicClient: dynamicClient,
+				ScaleClient:   scaleClient,
+				Recorder:      recorder,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create scheduler: %v", err)
+			}
+
+			// The policy is announced once, however many checks it applies to
+			for i := 0; i < 2; i++ {
+				if err := s.checkAndScale(context.Background()); err != nil {
+					t.Fatalf("checkAndScale() error = %v", err)
+				}
+			}
+
+			deployments := appsv1.SchemeGroupVersion.WithResource("deployments")
+			if replicas, _ := getReplicas(t, dynamicClient, deployments, "default", "test-deployment"); replicas != tt.wantReplicas {
+				t.Errorf("replicas = %d, want %d", replicas, tt.wantReplicas)
+			}
+
+			announced := 0
+			for _, entry := range logger.getEntries() {
+				if strings.Contains(entry, "applying "+tt.policy+" stale policy") {
+					announced++
+				}
+			}
+			if announced != 1 {
+				t.Errorf("stale policy logged %d times, want once", announced)
+			}
+			if event := <-recorder.Events; !strings.Contains(event, "StaleConfiguration") {
+				t.Errorf("event = %q, want StaleConfiguration", event)
+			}
+
+			// Fresh configuration lifts the policy
+			provider.mu.Lock()
+			provider.resources[0].Stale = nil
+			provider.mu.Unlock()
+			if err := s.checkAndScale(context.Background()); err != nil {
+				t.Fatalf("checkAndScale() error = %v", err)
+			}
+			if replicas, _ := getReplicas(t, dynamicClient, deployments, "default", "test-deployment"); replicas != 5 {
+				t.Errorf("replicas after refresh = %d, want 5", replicas)
+			}
+			if !containsEntry(logger.getEntries(), "stale policy lifted") {
+				t.Errorf("log entries %q do not report the policy being lifted", logger.getEntries())
+			}
+		})
+	}
+}
+
+func TestScheduler_CheckAndScale_StalePruned(t *testing.T) {
+	stale := model.Resource{
+		Name:             "test-scaler",
+		Namespace:        "default",
+		Target:           model.Target{Name: "test-deployment", Kind: "Deployment"},
+		OriginalReplicas: 2,
+		Stale:            &model.Staleness{Policy: model.StalePolicyFreeze, FetchedAt: time.Now().Add(-time.Hour)},
+	}
+	provider := &mockProvider{resources: []model.Resource{stale}}
+
+	logger := newTestLogger()
+	mapper, dynamicClient, scaleClient := fakeScaling(createTestDeployment("test-deployment", "default", 3))
+	s, err := New(provider, Options{
+		PollInterval:  time.Second,
+		Logger:        logger,
+		Client:        fake.NewSimpleClientset(),
+		Mapper:        mapper,
+		DynamicClient: dynamicClient,
+		ScaleClient:   scaleClient,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+
+	// A frozen resource is announced once, not on every check
+	for i := 0; i < 3; i++ {
+		if err := s.checkAndScale(context.Background()); err != nil {
+			t.Fatalf("checkAndScale() error = %v", err)
+		}
+	}
+	lines := 0
+	for _, entry := range logger.getEntries() {
+		if strings.Contains(entry, "test-scaler") {
+			lines++
+		}
+	}
+	if lines != 1 {
+		t.Errorf("logged %d lines for the frozen resource, want 1: %q", lines, logger.getEntries())
+	}
+
+	// Removing the resource from the configuration forgets its stale policy
+	provider.mu.Lock()
+	provider.resources = nil
+	provider.mu.Unlock()
+	if err := s.checkAndScale(context.Background()); err != nil {
+		t.Fatalf("checkAndScale() error = %v", err)
+	}
+	s.staleMu.Lock()
+	defer s.staleMu.Unlock()
+	if len(s.stale) != 0 {
+		t.Errorf("stale = %v, want the removed resource forgotten", s.stale)
+	}
+}
+
+// containsEntry reports whether any log entry contains substr
+func containsEntry(entries []string, substr string) bool {
+	for _, entry := range entries {
+		if strings.Contains(entry, substr) {
+			return true
+		}
+	}
+	return false
+}